@@ -0,0 +1,234 @@
+package zabbix
+
+import (
+	"context"
+	"errors"
+)
+
+const logoutMethod = "user.logout"
+const checkAuthenticationMethod = "user.checkAuthentication"
+
+// checkTokenMinAPIVersion is the first version whose "user.checkAuthentication"
+// accepts an API token.
+var checkTokenMinAPIVersion = APIVersion{Major: 6, Minor: 4, Patch: 0}
+
+// ErrNotAuthenticated is returned when a method which needs a session ID or
+// an API token is called before Login or without WithAPIToken.
+var ErrNotAuthenticated = errors.New("zabbix: not logged in and no API token set")
+
+// Credentials is a set of credentials to authenticate with the server.
+// Either APIToken, or Username and Password must be set.
+type Credentials struct {
+	APIToken string
+	Username string
+	Password string
+}
+
+// CredentialsProvider provides Credentials to a Client.
+type CredentialsProvider interface {
+	Credentials(ctx context.Context) (Credentials, error)
+}
+
+// CredentialsFunc is an adapter to allow the use of an ordinary function as
+// a CredentialsProvider.
+type CredentialsFunc func(ctx context.Context) (Credentials, error)
+
+// Credentials calls f(ctx).
+func (f CredentialsFunc) Credentials(ctx context.Context) (Credentials, error) {
+	return f(ctx)
+}
+
+// WithCredentialsProvider sets the provider of credentials.
+// The Client gets credentials from p and authenticates before the first call
+// which requires the auth, and again when a call fails because the session or
+// the API token has expired. So rotated API tokens or passwords are used
+// without creating a new Client.
+func WithCredentialsProvider(p CredentialsProvider) ClientOpt {
+	return func(c *Client) {
+		c.credentials = p
+	}
+}
+
+// SetAPIToken replaces the session ID or the API token used by the Client with
+// token. It is safe to call SetAPIToken concurrently with calls, and calls
+// started after SetAPIToken returns use the new token.
+func (c *Client) SetAPIToken(token string) {
+	c.authMu.Lock()
+	defer c.authMu.Unlock()
+	c.auth = token
+	c.authIsToken = true
+}
+
+// SetSessionID replaces the session ID or the API token used by the Client
+// with sessionID, which was returned by "user.login" for another Client.
+// It is safe to call SetSessionID concurrently with calls.
+func (c *Client) SetSessionID(sessionID string) {
+	c.authMu.Lock()
+	defer c.authMu.Unlock()
+	c.auth = sessionID
+	c.authIsToken = false
+}
+
+// SessionID returns the session ID set by Login or SetSessionID.
+// It returns an empty string if the Client has not logged in or uses an API
+// token.
+func (c *Client) SessionID() string {
+	auth, isToken := c.authInfo()
+	if isToken {
+		return ""
+	}
+	return auth
+}
+
+// authInfo returns the current session ID or API token.
+func (c *Client) authInfo() (auth string, isToken bool) {
+	c.authMu.RLock()
+	defer c.authMu.RUnlock()
+	return c.auth, c.authIsToken
+}
+
+// ensureAuth authenticates with the credentials provider if the Client has
+// not been authenticated yet. It returns the current auth.
+func (c *Client) ensureAuth(ctx context.Context) (string, error) {
+	auth, _ := c.authInfo()
+	if auth != "" || c.credentials == nil {
+		return auth, nil
+	}
+	if err := c.reauthenticate(ctx, auth); err != nil {
+		return "", err
+	}
+	auth, _ = c.authInfo()
+	return auth, nil
+}
+
+// canReauthenticate returns whether reauthenticate can get a new auth.
+func (c *Client) canReauthenticate() bool {
+	if c.credentials != nil {
+		return true
+	}
+	c.authMu.RLock()
+	defer c.authMu.RUnlock()
+	return c.reLogin && c.username != ""
+}
+
+// errAuthUnchanged is returned by reauthenticate if the credentials provider
+// returned the same API token as the failed one.
+var errAuthUnchanged = errors.New("zabbix: API token is unchanged")
+
+// reauthenticate gets a new auth with the credentials provider, or logs in
+// again with the kept username and password. failedAuth is the auth used by
+// the failed call. If another goroutine has already replaced it,
+// reauthenticate does nothing, so that concurrent calls failed with the same
+// expired session log in only once.
+func (c *Client) reauthenticate(ctx context.Context, failedAuth string) error {
+	c.reauthMu.Lock()
+	defer c.reauthMu.Unlock()
+
+	c.authMu.RLock()
+	auth, username, password := c.auth, c.username, c.password
+	c.authMu.RUnlock()
+	if auth != failedAuth {
+		return nil
+	}
+
+	if c.credentials == nil {
+		return c.Login(ctx, username, password)
+	}
+	creds, err := c.credentials.Credentials(ctx)
+	if err != nil {
+		return err
+	}
+	if creds.APIToken != "" {
+		if creds.APIToken == failedAuth {
+			return errAuthUnchanged
+		}
+		c.SetAPIToken(creds.APIToken)
+		return nil
+	}
+	return c.Login(ctx, creds.Username, creds.Password)
+}
+
+// Logout sends a "user.logout" request to the server to terminate the session
+// created by Login. The session ID and the credentials kept in the Client are
+// cleared even if the session has already expired on the server.
+// Logout returns an error if the Client uses an API token, since an API token
+// is not a session.
+func (c *Client) Logout(ctx context.Context) error {
+	auth, isToken := c.authInfo()
+	if auth == "" {
+		return ErrNotAuthenticated
+	}
+	if isToken {
+		return errors.New("zabbix: cannot log out with an API token")
+	}
+
+	// Call the call method instead of Call to avoid logging in again just to
+	// log out.
+	var result bool
+	err := c.call(ctx, logoutMethod, []string{}, &result)
+	if err != nil && !IsSessionExpired(err) {
+		return err
+	}
+	c.authMu.Lock()
+	defer c.authMu.Unlock()
+	if c.auth == auth {
+		c.auth = ""
+		c.username = ""
+		c.password = ""
+	}
+	return nil
+}
+
+// UserData is the information about the authenticated user returned by
+// CheckAuthentication.
+type UserData struct {
+	UserID    string `json:"userid"`
+	Username  string `json:"username"`
+	Name      string `json:"name"`
+	Surname   string `json:"surname"`
+	RoleID    string `json:"roleid"`
+	Type      string `json:"type"`
+	SessionID string `json:"sessionid"`
+}
+
+type checkAuthenticationParams struct {
+	SessionID string `json:"sessionid,omitempty"`
+	Token     string `json:"token,omitempty"`
+}
+
+// CheckAuthentication sends a "user.checkAuthentication" request to the server
+// to validate the session ID set by Login or the API token set by WithAPIToken.
+// If the Client has a CredentialsProvider, it authenticates first if needed.
+// It returns an error which satisfies IsSessionExpired if the session has
+// expired or the API token is invalid.
+// Note checking a session extends the lifetime of the session, and checking
+// an API token requires Zabbix 6.4 or later.
+func (c *Client) CheckAuthentication(ctx context.Context) (*UserData, error) {
+	if _, err := c.ensureAuth(ctx); err != nil {
+		return nil, err
+	}
+	auth, isToken := c.authInfo()
+	if auth == "" {
+		return nil, ErrNotAuthenticated
+	}
+
+	var params checkAuthenticationParams
+	if isToken {
+		apiVer, err := c.APIVersion(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if apiVer.Compare(checkTokenMinAPIVersion) < 0 {
+			return nil, errors.New("zabbix: checking an API token requires Zabbix 6.4 or later")
+		}
+		params.Token = auth
+	} else {
+		params.SessionID = auth
+	}
+
+	var user UserData
+	if err := c.Call(ctx, checkAuthenticationMethod, &params, &user); err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
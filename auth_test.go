@@ -0,0 +1,279 @@
+package zabbix
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+
+	"golang.org/x/exp/slices"
+)
+
+func TestClientLogout(t *testing.T) {
+	sessionExpired := &APIError{
+		Code:    ErrorCodeInvalidParams,
+		Message: "Invalid params.",
+		Data:    "Session terminated, re-login, please.",
+	}
+
+	testCases := []struct {
+		name         string
+		logoutResult any
+		wantErr      bool
+	}{
+		{name: "success", logoutResult: true, wantErr: false},
+		{name: "sessionExpired", logoutResult: sessionExpired, wantErr: false},
+		{name: "failure", logoutResult: &APIError{Code: ErrorCodeApplication,
+			Message: "Application error.", Data: "Cannot log out."}, wantErr: true},
+	}
+	for _, c := range testCases {
+		t.Run(c.name, func(t *testing.T) {
+			var received []testRequest
+			s := newTestServer(t, "6.0.16", &received, func(req testRequest) any {
+				switch req.Method {
+				case loginMethod:
+					return "session1"
+				case logoutMethod:
+					return c.logoutResult
+				}
+				return nil
+			})
+			client, err := NewClient(s.URL, WithReLogin(true))
+			if err != nil {
+				t.Fatal(err)
+			}
+			ctx := context.Background()
+			if err := client.Login(ctx, "Admin", "zabbix"); err != nil {
+				t.Fatal(err)
+			}
+			err = client.Logout(ctx)
+			if gotErr := err != nil; gotErr != c.wantErr {
+				t.Errorf("error mismatch, got=%v, wantErr=%v", err, c.wantErr)
+			}
+			if c.wantErr {
+				return
+			}
+
+			var logins int
+			for _, req := range received {
+				if req.Method == loginMethod {
+					logins++
+				}
+			}
+			if got, want := logins, 1; got != want {
+				t.Errorf("login count mismatch, got=%d, want=%d", got, want)
+			}
+			last := received[len(received)-1]
+			if got, want := last.Auth, "session1"; got != want {
+				t.Errorf("logout auth mismatch, got=%q, want=%q", got, want)
+			}
+			if err := client.Logout(ctx); !errors.Is(err, ErrNotAuthenticated) {
+				t.Errorf("second logout error mismatch, got=%v, want=%v", err, ErrNotAuthenticated)
+			}
+		})
+	}
+
+	t.Run("apiToken", func(t *testing.T) {
+		client, err := NewClient("http://127.0.0.1/zabbix/", WithAPIToken("token1"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := client.Logout(context.Background()); err == nil {
+			t.Error("logout with API token should fail")
+		}
+	})
+}
+
+func TestClientCheckAuthentication(t *testing.T) {
+	testCases := []struct {
+		name       string
+		apiVersion string
+		token      bool
+		wantParams string
+		wantErr    bool
+	}{
+		{name: "session", apiVersion: "6.0.16",
+			wantParams: `{"sessionid":"session1"}`},
+		{name: "token", apiVersion: "6.4.0", token: true,
+			wantParams: `{"token":"token1"}`},
+		{name: "tokenOldVersion", apiVersion: "6.0.16", token: true, wantErr: true},
+	}
+	for _, c := range testCases {
+		t.Run(c.name, func(t *testing.T) {
+			var received []testRequest
+			s := newTestServer(t, c.apiVersion, &received, func(req testRequest) any {
+				switch req.Method {
+				case loginMethod:
+					return "session1"
+				case checkAuthenticationMethod:
+					return map[string]string{"userid": "1", "username": "Admin"}
+				}
+				return nil
+			})
+			var opts []ClientOpt
+			if c.token {
+				opts = append(opts, WithAPIToken("token1"))
+			}
+			client, err := NewClient(s.URL, opts...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			ctx := context.Background()
+			if !c.token {
+				if err := client.Login(ctx, "Admin", "zabbix"); err != nil {
+					t.Fatal(err)
+				}
+			}
+			user, err := client.CheckAuthentication(ctx)
+			if c.wantErr {
+				if err == nil {
+					t.Error("want error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got, want := user.Username, "Admin"; got != want {
+				t.Errorf("username mismatch, got=%q, want=%q", got, want)
+			}
+
+			last := received[len(received)-1]
+			if got, want := last.Method, checkAuthenticationMethod; got != want {
+				t.Fatalf("method mismatch, got=%q, want=%q", got, want)
+			}
+			if last.Auth != "" || last.Authorization != "" {
+				t.Errorf("auth must not be sent, got auth=%q, authorization=%q",
+					last.Auth, last.Authorization)
+			}
+			if got, want := string(last.Params), c.wantParams; got != want {
+				t.Errorf("params mismatch, got=%s, want=%s", got, want)
+			}
+		})
+	}
+}
+
+func TestClientCredentialsProvider(t *testing.T) {
+	tokenExpired := &APIError{
+		Code:    ErrorCodeInvalidParams,
+		Message: "Invalid params.",
+		Data:    "API token expired.",
+	}
+
+	var received []testRequest
+	s := newTestServer(t, "6.0.16", &received, func(req testRequest) any {
+		if req.Auth != "token2" {
+			return tokenExpired
+		}
+		return "3"
+	})
+	tokens := []string{"token1", "token2"}
+	var provided int
+	client, err := NewClient(s.URL, WithCredentialsProvider(CredentialsFunc(
+		func(ctx context.Context) (Credentials, error) {
+			token := tokens[provided]
+			provided++
+			return Credentials{APIToken: token}, nil
+		})))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var count string
+	if err := client.Call(context.Background(), "host.get", map[string]bool{"countOutput": true}, &count); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := provided, 2; got != want {
+		t.Errorf("provided count mismatch, got=%d, want=%d", got, want)
+	}
+
+	var gotAuths []string
+	for _, req := range received {
+		if req.Method == "host.get" {
+			gotAuths = append(gotAuths, req.Auth)
+		}
+	}
+	if got, want := gotAuths, []string{"token1", "token2"}; !slices.Equal(got, want) {
+		t.Errorf("auths mismatch, got=%v, want=%v", got, want)
+	}
+
+	// The call is not retried if the provider returns the same token.
+	received = nil
+	tokens = []string{"token1", "token1"}
+	provided = 0
+	client, err = NewClient(s.URL, WithCredentialsProvider(CredentialsFunc(
+		func(ctx context.Context) (Credentials, error) {
+			token := tokens[provided]
+			provided++
+			return Credentials{APIToken: token}, nil
+		})))
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = client.Call(context.Background(), "host.get", map[string]bool{"countOutput": true}, &count)
+	if !IsSessionExpired(err) {
+		t.Errorf("want token expired error, got=%v", err)
+	}
+	var calls int
+	for _, req := range received {
+		if req.Method == "host.get" {
+			calls++
+		}
+	}
+	if got, want := calls, 1; got != want {
+		t.Errorf("call count mismatch for unchanged token, got=%d, want=%d", got, want)
+	}
+}
+
+func TestClientConcurrentReLogin(t *testing.T) {
+	sessionExpired := &APIError{
+		Code:    ErrorCodeInvalidParams,
+		Message: "Invalid params.",
+		Data:    "Session terminated, re-login, please.",
+	}
+
+	var received []testRequest
+	var loginCount int
+	s := newTestServer(t, "6.0.16", &received, func(req testRequest) any {
+		switch req.Method {
+		case loginMethod:
+			loginCount++
+			return fmt.Sprintf("session%d", loginCount)
+		case "host.get":
+			if req.Auth == "session1" {
+				return sessionExpired
+			}
+			return "3"
+		}
+		return nil
+	})
+	client, err := NewClient(s.URL, WithReLogin(true))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	if err := client.Login(ctx, "Admin", "zabbix"); err != nil {
+		t.Fatal(err)
+	}
+
+	const n = 8
+	var wg sync.WaitGroup
+	errs := make([]error, n)
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			var count string
+			errs[i] = client.Call(ctx, "host.get", map[string]bool{"countOutput": true}, &count)
+		}(i)
+	}
+	wg.Wait()
+	for i, err := range errs {
+		if err != nil {
+			t.Errorf("call %d failed: %v", i, err)
+		}
+	}
+	if got, want := loginCount, 2; got != want {
+		t.Errorf("login count mismatch, got=%d, want=%d", got, want)
+	}
+}
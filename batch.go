@@ -0,0 +1,263 @@
+package zabbix
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+	"time"
+)
+
+// Batch is a set of calls which are sent to the server in a single
+// JSON-RPC batch request with Client.CallBatch.
+// https://www.jsonrpc.org/specification#batch
+type Batch struct {
+	calls []batchCall
+}
+
+type batchCall struct {
+	method string
+	params any
+	result any
+}
+
+// Add queues a call to the batch.
+// The caller of this method must pass a pointer to the appropriate type of
+// result like Client.Call. The result is set by Client.CallBatch.
+func (b *Batch) Add(method string, params, result any) {
+	b.calls = append(b.calls, batchCall{
+		method: method,
+		params: params,
+		result: result,
+	})
+}
+
+// Len returns the number of calls in the batch.
+func (b *Batch) Len() int {
+	return len(b.calls)
+}
+
+// BatchError is the error type returned by Client.CallBatch method
+// when one or more calls in a batch failed.
+type BatchError struct {
+	// Errs has the same length as the calls in the batch.
+	// Errs[i] is nil if the i-th call succeeded, or a CallError otherwise.
+	Errs []error
+}
+
+var _ error = (*BatchError)(nil)
+
+func (e *BatchError) Error() string {
+	var b strings.Builder
+	b.WriteString("batch call failed: ")
+	first := true
+	for _, err := range e.Errs {
+		if err == nil {
+			continue
+		}
+		if !first {
+			b.WriteString(", ")
+		}
+		b.WriteString(err.Error())
+		first = false
+	}
+	return b.String()
+}
+
+func (e *BatchError) Unwrap() []error {
+	var errs []error
+	for _, err := range e.Errs {
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errs
+}
+
+// CallBatch sends the calls in b to the server in a single JSON-RPC batch
+// request, and sets the results of succeeded calls.
+// Responses are matched to calls by their IDs.
+// If one or more calls failed, CallBatch returns a BatchError.
+// The methods "user.login", "user.checkAuthentication" and "apiinfo.version"
+// cannot be used in a batch.
+func (c *Client) CallBatch(ctx context.Context, b *Batch) error {
+	if b.Len() == 0 {
+		return nil
+	}
+	auth, err := c.ensureAuth(ctx)
+	if err != nil {
+		return err
+	}
+	err = c.callBatch(ctx, b)
+	if c.shouldReLoginBatch(b, err) {
+		c.logger.LogAttrs(ctx, slog.LevelInfo, "session expired, logging in again",
+			slog.Int("calls", b.Len()))
+		if err2 := c.reauthenticate(ctx, auth); err2 != nil {
+			if errors.Is(err2, errAuthUnchanged) {
+				return err
+			}
+			return err2
+		}
+		return c.callBatch(ctx, b)
+	}
+	return err
+}
+
+// shouldReLoginBatch returns whether the batch should be retried after
+// logging in again. It returns true only if all calls in the batch failed
+// because of the expired session, so succeeded calls are never sent twice.
+func (c *Client) shouldReLoginBatch(b *Batch, err error) bool {
+	var batchErr *BatchError
+	if !errors.As(err, &batchErr) {
+		return false
+	}
+	for i, callErr := range batchErr.Errs {
+		if !c.shouldReLogin(b.calls[i].method, callErr) {
+			return false
+		}
+	}
+	return true
+}
+
+func (c *Client) callBatch(ctx context.Context, b *Batch) error {
+	if b.Len() == 0 {
+		return nil
+	}
+	for _, call := range b.calls {
+		if !methodRequiresAuth(call.method) {
+			return fmt.Errorf("method %q cannot be used in a batch", call.method)
+		}
+	}
+
+	auth, _ := c.authInfo()
+	authInHeader, err := c.useAuthHeader(ctx, b.calls[0].method, auth)
+	if err != nil {
+		return err
+	}
+	reqs := make([]*rpcRequest, b.Len())
+	for i, call := range b.calls {
+		reqs[i] = c.newRPCRequest(call.method, call.params, auth, authInHeader)
+	}
+
+	var authHeader string
+	if authInHeader {
+		authHeader = auth
+	}
+	start := time.Now()
+	var body []byte
+	statusCode, respBody, postErr := c.post(ctx, reqs, authHeader,
+		func(r io.Reader) error {
+			var err error
+			body, err = io.ReadAll(r)
+			return err
+		})
+	duration := time.Since(start)
+	for _, req := range reqs {
+		req.statusCode = statusCode
+		req.respBodyBytes = body
+		if body == nil {
+			req.respBodyBytes = respBody
+		}
+	}
+	if postErr != nil {
+		err = newBatchError(reqs, func(*rpcRequest) error { return postErr })
+	} else {
+		err = setBatchResults(b, reqs, body)
+	}
+	if c.debug {
+		c.debugBatchCall(ctx, reqs, authHeader != "", duration, statusCode, respBody, err)
+	}
+	return err
+}
+
+func setBatchResults(b *Batch, reqs []*rpcRequest, respBody []byte) error {
+	type response struct {
+		responseCommon
+		Result json.RawMessage `json:"result"`
+	}
+
+	var responses []response
+	if err := json.Unmarshal(respBody, &responses); err != nil {
+		// The server returns a single error object if the whole batch
+		// is invalid.
+		var res responseCommon
+		if err2 := json.Unmarshal(respBody, &res); err2 == nil && res.Error != nil {
+			err = res.Error
+		}
+		return newBatchError(reqs, func(*rpcRequest) error { return err })
+	}
+
+	responsesByID := make(map[uint64]response, len(responses))
+	for _, res := range responses {
+		responsesByID[res.ID] = res
+	}
+
+	var failed bool
+	batchErr := newBatchError(reqs, func(req *rpcRequest) error {
+		res, ok := responsesByID[req.ID]
+		if !ok {
+			failed = true
+			return fmt.Errorf("response for request ID (%d) is missing", req.ID)
+		}
+		if res.Error != nil {
+			failed = true
+			return res.Error
+		}
+		return nil
+	})
+	for i, req := range reqs {
+		if batchErr.Errs[i] != nil {
+			continue
+		}
+		if err := json.Unmarshal(responsesByID[req.ID].Result, b.calls[i].result); err != nil {
+			failed = true
+			batchErr.Errs[i] = newCallError(req, err)
+		}
+	}
+	if failed {
+		return batchErr
+	}
+	return nil
+}
+
+// newBatchError returns a BatchError whose i-th element is a CallError
+// wrapping getErr(reqs[i]), or nil if getErr returns nil.
+func newBatchError(reqs []*rpcRequest, getErr func(req *rpcRequest) error) *BatchError {
+	errs := make([]error, len(reqs))
+	for i, req := range reqs {
+		if err := getErr(req); err != nil {
+			errs[i] = newCallError(req, err)
+		}
+	}
+	return &BatchError{Errs: errs}
+}
+
+func (c *Client) debugBatchCall(ctx context.Context, reqs []*rpcRequest, authInHeader bool, duration time.Duration, statusCode int, respBody []byte, err error) {
+	reqs2 := make([]rpcRequest, len(reqs))
+	methods := make([]string, len(reqs))
+	for i, req := range reqs {
+		reqs2[i] = req.redacted()
+		methods[i] = req.Method
+	}
+	reqs2Bytes, err2 := json.Marshal(reqs2)
+	if err2 != nil {
+		panic(err2)
+	}
+	attrs := []slog.Attr{
+		slog.Any("methods", methods),
+		slog.Duration("duration", duration),
+		slog.Int("status_code", statusCode),
+		slog.String("request", string(reqs2Bytes)),
+	}
+	if authInHeader {
+		attrs = append(attrs, slog.String("authorization", "Bearer "+hiddenSecretForLog))
+	}
+	attrs = append(attrs, slog.String("response", string(respBody)))
+	if err != nil {
+		attrs = append(attrs, slog.Any("err", err))
+	}
+	c.logger.LogAttrs(ctx, slog.LevelDebug, "batch call", attrs...)
+}
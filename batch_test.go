@@ -0,0 +1,89 @@
+package zabbix
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestClientCallBatch(t *testing.T) {
+	var received []testRequest
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&received); err != nil {
+			t.Errorf("decode request: %v", err)
+			return
+		}
+		// Respond in reverse order to check responses are matched by IDs.
+		var responses []map[string]any
+		for i := len(received) - 1; i >= 0; i-- {
+			req := received[i]
+			res := map[string]any{"jsonrpc": jsonrpcVersion, "id": req.ID}
+			var params struct {
+				TriggerID string `json:"triggerid"`
+			}
+			if err := json.Unmarshal(req.Params, &params); err != nil {
+				t.Errorf("decode params: %v", err)
+			}
+			if params.TriggerID == "2" {
+				res["error"] = &APIError{
+					Code:    ErrorCodeInvalidParams,
+					Message: "Invalid params.",
+					Data:    "No permissions to referred object or it does not exist!",
+				}
+			} else {
+				res["result"] = map[string]any{"triggerids": []string{params.TriggerID}}
+			}
+			responses = append(responses, res)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(responses); err != nil {
+			t.Errorf("encode response: %v", err)
+		}
+	}))
+	defer s.Close()
+
+	client, err := NewClient(s.URL, WithAPIToken("token1"), WithAuthMethod(AuthMethodField))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	type TriggerIDs struct {
+		TriggerIDs []string `json:"triggerids"`
+	}
+	triggerIDs := []string{"1", "2", "3"}
+	results := make([]TriggerIDs, len(triggerIDs))
+	var b Batch
+	for i, id := range triggerIDs {
+		b.Add("trigger.update", map[string]string{"triggerid": id, "status": "1"}, &results[i])
+	}
+	err = client.CallBatch(context.Background(), &b)
+
+	if got, want := len(received), len(triggerIDs); got != want {
+		t.Fatalf("request count mismatch, got=%d, want=%d", got, want)
+	}
+	if received[0].ID == received[1].ID {
+		t.Errorf("request IDs must be unique, got=%d", received[0].ID)
+	}
+
+	var batchErr *BatchError
+	if !errors.As(err, &batchErr) {
+		t.Fatalf("error type mismatch, got=%T, want=*BatchError", err)
+	}
+	for i, id := range triggerIDs {
+		if id == "2" {
+			if got, want := GetErrorCode(batchErr.Errs[i]), ErrorCodeInvalidParams; got != want {
+				t.Errorf("error code mismatch, i=%d, got=%d, want=%d", i, got, want)
+			}
+			continue
+		}
+		if batchErr.Errs[i] != nil {
+			t.Errorf("unexpected error, i=%d, err=%v", i, batchErr.Errs[i])
+		}
+		if got := results[i].TriggerIDs; len(got) != 1 || got[0] != id {
+			t.Errorf("result mismatch, i=%d, got=%v, want=[%s]", i, got, id)
+		}
+	}
+}
@@ -7,34 +7,96 @@ import (
 	"errors"
 	"fmt"
 	"io"
-	"log"
+	"log/slog"
 	"net/http"
 	"net/url"
+	"os"
 	"sync"
 	"sync/atomic"
+	"time"
 )
 
 const contentType = "application/json-rpc"
 const jsonrpcVersion = "2.0"
 const jsonrpcFilename = "api_jsonrpc.php"
 const loginMethod = "user.login"
+const apiVersionMethod = "apiinfo.version"
+
+// authHeaderMinAPIVersion is the first version which accepts the
+// "Authorization: Bearer" header. The "auth" property of requests is
+// deprecated since this version and removed in Zabbix 7.2.
+var authHeaderMinAPIVersion = APIVersion{Major: 6, Minor: 4, Patch: 0}
+
+// authFieldRemovedAPIVersion is the first version which does not accept the
+// "auth" property of requests.
+var authFieldRemovedAPIVersion = APIVersion{Major: 7, Minor: 2, Patch: 0}
+
+// ErrAuthHeaderInUse is returned when the auth cannot be sent since
+// the server requires the "Authorization" header for the auth, but the header
+// is already set for a reverse proxy (e.g. WithBasicAuth).
+var ErrAuthHeaderInUse = errors.New("zabbix: cannot send the auth since Zabbix 7.2 or later requires the Authorization header, which is already used for a reverse proxy")
 
 // Client represents a client for Zabbix JSON-RPC API.
+// A Client is safe for concurrent use by multiple goroutines.
 type Client struct {
 	httpClient *http.Client
 	apiURL     string
 	host       string
 	debug      bool
+	authMethod AuthMethod
+	reLogin    bool
+
+	retryPolicy    *RetryPolicy
+	rateLimiter    *rateLimiter
+	concurrencySem chan struct{}
+	limiterStats   limiterStats
+
+	interceptors []Interceptor
+	invoker      Invoker
+	logger       *slog.Logger
+
+	maxResponseSize int64
+
+	transportOpts transportOptions
+	header        http.Header
+	timeout       time.Duration
 
 	requestID atomic.Uint64
-	auth      string
 
-	apiVerOnce sync.Once
-	apiVer     APIVersion
+	// authMu guards auth, authIsToken, username and password.
+	authMu sync.RWMutex
+	auth   string
+	// authIsToken is true if auth is an API token instead of a session ID.
+	authIsToken bool
+	// username and password are kept only if reLogin is true.
+	username string
+	password string
+
+	credentials CredentialsProvider
+	// reauthMu serializes re-authentications after sessions expired.
+	reauthMu sync.Mutex
+
+	// apiVer is set only when the API version is got successfully, so that
+	// a failed request is retried by the next call of APIVersion.
+	apiVerMu sync.Mutex
+	apiVer   *APIVersion
 }
 
 type ClientOpt func(c *Client)
 
+// AuthMethod specifies how a session ID or an API token is sent to the server.
+type AuthMethod int
+
+const (
+	// AuthMethodAuto selects AuthMethodHeader if the API version of the
+	// server is 6.4.0 or later, or AuthMethodField otherwise.
+	AuthMethodAuto AuthMethod = iota
+	// AuthMethodHeader sends the auth with "Authorization: Bearer" header.
+	AuthMethodHeader
+	// AuthMethodField sends the auth with "auth" property in requests.
+	AuthMethodField
+)
+
 func WithHost(host string) ClientOpt {
 	return func(c *Client) {
 		c.host = host
@@ -47,15 +109,55 @@ func WithHTTPClient(httpClient *http.Client) ClientOpt {
 	}
 }
 
+// WithLogger sets the logger for the Client.
+// Requests and responses are logged at the debug level if WithDebug(true) is
+// also set. Retries and re-logins are logged at the warning and info levels.
+// The default is a logger writing to the standard error at the debug level
+// if WithDebug(true) is set, or slog.Default() otherwise.
+func WithLogger(logger *slog.Logger) ClientOpt {
+	return func(c *Client) {
+		c.logger = logger
+	}
+}
+
+// WithDebug enables logging of requests and responses.
 func WithDebug(debug bool) ClientOpt {
 	return func(c *Client) {
 		c.debug = debug
 	}
 }
 
+// WithAuthMethod overrides how the auth is sent to the server.
+// The default is AuthMethodAuto.
+func WithAuthMethod(method AuthMethod) ClientOpt {
+	return func(c *Client) {
+		c.authMethod = method
+	}
+}
+
+// WithReLogin makes Login keep the username and password in the Client.
+// When a call fails because the session has expired, the Client logs in
+// again with them and retries the call once.
+// This has no effect for clients which use an API token instead of Login.
+func WithReLogin(reLogin bool) ClientOpt {
+	return func(c *Client) {
+		c.reLogin = reLogin
+	}
+}
+
 func WithAPIToken(token string) ClientOpt {
 	return func(c *Client) {
 		c.auth = token
+		c.authIsToken = true
+	}
+}
+
+// WithAPIVersion sets the API version of the server, so that the Client does
+// not send an "apiinfo.version" request. It is useful when the version is
+// cached, e.g. with a session ID.
+func WithAPIVersion(ver APIVersion) ClientOpt {
+	return func(c *Client) {
+		c.apiVer = &ver
 	}
 }
 
@@ -73,9 +175,25 @@ func NewClient(zabbixURL string, opts ...ClientOpt) (*Client, error) {
 		return nil, err
 	}
 	c.apiURL = u.JoinPath(jsonrpcFilename).String()
-	if c.httpClient == nil {
-		c.httpClient = http.DefaultClient
+	if err := c.configureHTTPClient(); err != nil {
+		return nil, err
+	}
+
+	if c.logger == nil {
+		if c.debug {
+			c.logger = slog.New(slog.NewTextHandler(os.Stderr,
+				&slog.HandlerOptions{Level: slog.LevelDebug}))
+		} else {
+			c.logger = slog.Default()
+		}
 	}
+
+	interceptors := c.interceptors
+	if c.debug {
+		interceptors = append(interceptors[:len(interceptors):len(interceptors)],
+			c.debugInterceptor)
+	}
+	c.invoker = chainInterceptors(interceptors, c.invoke)
 	return c, nil
 }
 
@@ -93,6 +211,8 @@ type oldLoginParams struct {
 // If the login is successful, the session ID will be returned from the server.
 // It is kept in the Client and it will be set to requests created with Call
 // method called after this call of Login method.
+// If the Client is created with WithReLogin(true), username and password are
+// also kept to log in again after the session has expired.
 func (c *Client) Login(ctx context.Context, username, password string) error {
 	apiVer, err := c.APIVersion(ctx)
 	if err != nil {
@@ -116,61 +236,151 @@ func (c *Client) Login(ctx context.Context, username, password string) error {
 		return errors.New("user.login API should have return a valid (non-empty) auth")
 	}
 
+	c.authMu.Lock()
 	c.auth = auth
+	c.authIsToken = false
+	if c.reLogin {
+		c.username = username
+		c.password = password
+	}
+	c.authMu.Unlock()
 	return nil
 }
 
 // Call sends a JSON-RPC request to the server.
 // The caller of this method must pass a pointer to the appropriate type of result.
 // The appropriate type is different for method and params.
+// Call can be called concurrently from multiple goroutines.
 func (c *Client) Call(ctx context.Context, method string, params, result any) error {
-	type responseCommon struct {
-		Jsonrpc string    `json:"jsonrpc"`
-		Error   *APIError `json:"error"`
-		ID      uint64    `json:"id"`
+	var auth string
+	if methodRequiresAuth(method) {
+		var err error
+		if auth, err = c.ensureAuth(ctx); err != nil {
+			return err
+		}
 	}
+	err := c.call(ctx, method, params, result)
+	if c.shouldReLogin(method, err) {
+		c.logger.LogAttrs(ctx, slog.LevelInfo, "session expired, logging in again",
+			slog.String("method", method))
+		if err2 := c.reauthenticate(ctx, auth); err2 != nil {
+			if errors.Is(err2, errAuthUnchanged) {
+				return err
+			}
+			return err2
+		}
+		return c.call(ctx, method, params, result)
+	}
+	return err
+}
 
-	var res struct {
-		responseCommon
-		Result any `json:"result"`
+// shouldReLogin returns whether the call of method failed with err should be
+// retried after logging in again.
+func (c *Client) shouldReLogin(method string, err error) bool {
+	return err != nil && methodRequiresAuth(method) && IsSessionExpired(err) &&
+		c.canReauthenticate()
+}
+
+func (c *Client) call(ctx context.Context, method string, params, result any) error {
+	for attempt := 1; ; attempt++ {
+		req, err := c.callOnce(ctx, method, params, result)
+		if err == nil || c.retryPolicy == nil ||
+			!c.retryPolicy.shouldRetry(method, req, err, attempt) {
+			return err
+		}
+		if s, ok := result.(*resultStreamer); ok && s.started {
+			// Do not retry since some elements have been passed to the callback.
+			return err
+		}
+		c.logger.LogAttrs(ctx, slog.LevelWarn, "retrying call",
+			slog.String("method", method),
+			slog.Int("attempt", attempt),
+			slog.Any("err", err))
+		if err := c.retryPolicy.sleep(ctx, attempt); err != nil {
+			return err
+		}
 	}
-	res.Result = result
-	req, err := c.internalCall(ctx, method, params, &res)
-	if c.debug {
-		c.debugRPCCall(req, err)
+}
+
+// callOnce sends a JSON-RPC request to the server through the interceptors.
+// The returned req is nil if the error occurred before creating the request.
+func (c *Client) callOnce(ctx context.Context, method string, params, result any) (req *rpcRequest, err error) {
+	call := &CallInfo{
+		Method: method,
+		Params: params,
+		Result: result,
 	}
+	err = c.invoker(ctx, call)
+	return call.req, err
+}
+
+// invoke is the innermost Invoker which actually sends a request.
+func (c *Client) invoke(ctx context.Context, call *CallInfo) error {
+	res := response{Result: call.Result}
+	auth, _ := c.authInfo()
+	authInHeader, err := c.useAuthHeader(ctx, call.Method, auth)
 	if err != nil {
-		return &CallError{
-			ID:     req.ID,
-			Method: req.Method,
-			Params: req.Params,
-			Err:    err,
-		}
+		return err
+	}
+	start := time.Now()
+	req, err := c.internalCall(ctx, call.Method, call.Params, auth, authInHeader, &res)
+	call.Duration = time.Since(start)
+	call.RequestID = req.ID
+	call.StatusCode = req.statusCode
+	call.req = req
+	if err != nil {
+		return newCallError(req, err)
 	}
 	if res.Error != nil {
-		return &CallError{
-			ID:     req.ID,
-			Method: req.Method,
-			Params: req.Params,
-			Err:    res.Error,
-		}
+		return newCallError(req, res.Error)
 	}
 	if res.ID != req.ID {
-		return &CallError{
-			ID:     req.ID,
-			Method: req.Method,
-			Params: req.Params,
-			Err: fmt.Errorf("response ID (%d) does not match resquest ID (%d)",
-				res.ID, req.ID),
-		}
+		return newCallError(req, fmt.Errorf("response ID (%d) does not match resquest ID (%d)",
+			res.ID, req.ID))
 	}
 	return nil
 }
 
+type responseCommon struct {
+	Jsonrpc string    `json:"jsonrpc"`
+	Error   *APIError `json:"error"`
+	ID      uint64    `json:"id"`
+}
+
+type response struct {
+	responseCommon
+	Result any `json:"result"`
+}
+
 const hiddenSecretForLog = "(secret)"
 
-func (c *Client) debugRPCCall(req *rpcRequest, err error) {
-	req2 := *req
+func (c *Client) debugRPCCall(ctx context.Context, call *CallInfo, err error) {
+	req := call.req
+	req2 := req.redacted()
+	req2Bytes, err2 := json.Marshal(req2)
+	if err2 != nil {
+		panic(err2)
+	}
+	attrs := []slog.Attr{
+		slog.String("method", req.Method),
+		slog.Uint64("request_id", req.ID),
+		slog.Duration("duration", call.Duration),
+		slog.Int("status_code", req.statusCode),
+		slog.String("request", string(req2Bytes)),
+	}
+	if req2.authHeader != "" {
+		attrs = append(attrs, slog.String("authorization", "Bearer "+req2.authHeader))
+	}
+	attrs = append(attrs, slog.String("response", string(req.respBodyBytes)))
+	if err != nil {
+		attrs = append(attrs, slog.Any("err", err))
+	}
+	c.logger.LogAttrs(ctx, slog.LevelDebug, "call", attrs...)
+}
+
+// redacted returns a copy of the request whose secrets are replaced for logging.
+func (r *rpcRequest) redacted() rpcRequest {
+	req2 := *r
 	if req2.Method == loginMethod {
 		if p, ok := req2.Params.(*loginParams); ok {
 			req2.Params = &loginParams{
@@ -184,104 +394,48 @@ func (c *Client) debugRPCCall(req *rpcRequest, err error) {
 			}
 		}
 	}
+	if p, ok := req2.Params.(*checkAuthenticationParams); ok {
+		p2 := *p
+		if p2.SessionID != "" {
+			p2.SessionID = hiddenSecretForLog
+		}
+		if p2.Token != "" {
+			p2.Token = hiddenSecretForLog
+		}
+		req2.Params = &p2
+	}
 	if req2.Auth != nil && req2.Auth != "" {
 		req2.Auth = hiddenSecretForLog
 	}
-	req2Bytes, err2 := json.Marshal(req2)
-	if err2 != nil {
-		panic(err2)
-	}
-	log.Printf("DEBUG request=%s, response=%s, status=%d, err=%v",
-		string(req2Bytes), string(req.respBodyBytes), req.statusCode, err)
-}
-
-// CallError is the error type returned by Client.Call method.
-// The concret type of the Err field is APIError or other error.
-type CallError struct {
-	ID     uint64 `json:"id"`
-	Method string `json:"method"`
-	Params any    `json:"params"`
-	Err    error  `json:"error"`
-}
-
-var _ error = (*CallError)(nil)
-
-func (e *CallError) Error() string {
-	data, err := json.Marshal(e)
-	if err != nil {
-		panic(err)
-	}
-	return string(data)
-}
-
-func (e *CallError) Unwrap() error {
-	return e.Err
-}
-
-type ErrorCode int
-
-const (
-	// ErrorCodeNone represents the error which is not an error object from
-	// Zabbix JSON-RPC API but some other error occurred in the client side.
-	ErrorCodeNone ErrorCode = 0
-
-	ErrorCodeParse          ErrorCode = -32700
-	ErrorCodeInvalidRequest ErrorCode = -32600
-	ErrorCodeMethodNotFound ErrorCode = -32601
-	ErrorCodeInvalidParams  ErrorCode = -32602
-	ErrorCodeInternal       ErrorCode = -32603
-	ErrorCodeApplication    ErrorCode = -32500
-	ErrorCodeSystem         ErrorCode = -32400
-	ErrorCodeTransport      ErrorCode = -32300
-)
-
-// GetErrorCode returns the Code field if err or a unwrapped error is APIError
-// or ErrorCodeNone otherwise.
-func GetErrorCode(err error) ErrorCode {
-	var apiErr *APIError
-	if errors.As(err, &apiErr) {
-		return apiErr.Code
-	}
-	return ErrorCodeNone
-}
-
-// APIError is an error object in responses from Zabbix JSON-RPC API.
-// https://www.zabbix.com/documentation/current/en/manual/api#error-handling
-type APIError struct {
-	Code    ErrorCode `json:"code"`
-	Message string    `json:"message"`
-	Data    string    `json:"data"`
-}
-
-var _ error = (*APIError)(nil)
-
-func (e *APIError) Error() string {
-	data, err := json.Marshal(e)
-	if err != nil {
-		panic(err)
+	if req2.authHeader != "" {
+		req2.authHeader = hiddenSecretForLog
 	}
-	return string(data)
+	return req2
 }
 
 // APIVersion returns APIVersion.
 // For the first call of this method, a request is sent to the server and
-// the result will be cached.
-// For subsequent call of this method, it returns the cached value.
+// the result will be cached if it succeeds.
+// For subsequent call of this method, it returns the cached value, or sends
+// a request again if the previous one failed.
 func (c *Client) APIVersion(ctx context.Context) (APIVersion, error) {
-	var err error
-	c.apiVerOnce.Do(func() {
-		c.apiVer, err = c.getAPIVersion(ctx)
-	})
+	c.apiVerMu.Lock()
+	defer c.apiVerMu.Unlock()
+	if c.apiVer != nil {
+		return *c.apiVer, nil
+	}
+	v, err := c.getAPIVersion(ctx)
 	if err != nil {
-		return c.apiVer, err
+		return APIVersion{}, err
 	}
-	return c.apiVer, nil
+	c.apiVer = &v
+	return v, nil
 }
 
 func (c *Client) getAPIVersion(ctx context.Context) (APIVersion, error) {
 	var v APIVersion
 	var ver string
-	if err := c.Call(ctx, "apiinfo.version", []string{}, &ver); err != nil {
+	if err := c.Call(ctx, apiVersionMethod, []string{}, &ver); err != nil {
 		return v, err
 	}
 	v, err := ParseAPIVersion(ver)
@@ -291,28 +445,106 @@ func (c *Client) getAPIVersion(ctx context.Context) (APIVersion, error) {
 	return v, nil
 }
 
-func (c *Client) internalCall(ctx context.Context, method string, params, result any) (req *rpcRequest, err error) {
-	req = c.newRPCRequest(method, params)
-	httpReq, err := c.newHTTPRequestWithContext(ctx, req)
+// useAuthHeader returns whether the auth should be sent with
+// "Authorization: Bearer" header instead of "auth" property for the method.
+func (c *Client) useAuthHeader(ctx context.Context, method, auth string) (bool, error) {
+	if auth == "" || !methodRequiresAuth(method) {
+		return false, nil
+	}
+	switch c.authMethod {
+	case AuthMethodHeader:
+		return true, nil
+	case AuthMethodField:
+		return false, nil
+	}
+	apiVer, err := c.APIVersion(ctx)
+	if err != nil {
+		return false, err
+	}
+	if c.header.Get("Authorization") != "" {
+		// The header is used for a reverse proxy (e.g. WithBasicAuth).
+		if apiVer.Compare(authFieldRemovedAPIVersion) >= 0 {
+			return false, ErrAuthHeaderInUse
+		}
+		return false, nil
+	}
+	return apiVer.Compare(authHeaderMinAPIVersion) >= 0, nil
+}
+
+// methodRequiresAuth returns false for methods which must be called without
+// the auth.
+func methodRequiresAuth(method string) bool {
+	return method != loginMethod && method != apiVersionMethod &&
+		method != checkAuthenticationMethod
+}
+
+func (c *Client) internalCall(ctx context.Context, method string, params any, auth string, authInHeader bool, res *response) (req *rpcRequest, err error) {
+	req = c.newRPCRequest(method, params, auth, authInHeader)
+	req.statusCode, req.respBodyBytes, err = c.post(ctx, req, req.authHeader,
+		func(r io.Reader) error {
+			if s, ok := res.Result.(*resultStreamer); ok {
+				return s.decodeResponse(r, &res.responseCommon)
+			}
+			return json.NewDecoder(r).Decode(res)
+		})
+	return req, err
+}
+
+// post sends body encoded in JSON to the server and decodes the response body
+// with decode. It returns an HTTPError if the status code is not 2xx or the
+// content type is not JSON. It returns the status code, and also the response body if
+// debug is enabled, or the beginning of it if decode fails.
+func (c *Client) post(ctx context.Context, body any, authHeader string, decode func(r io.Reader) error) (statusCode int, respBody []byte, err error) {
+	httpReq, err := c.newHTTPRequestWithContext(ctx, body, authHeader)
+	if err != nil {
+		return 0, nil, err
+	}
+	release, err := c.acquire(ctx)
 	if err != nil {
-		return req, err
+		return 0, nil, err
 	}
+	defer release()
 	httpRes, err := c.httpClient.Do(httpReq)
 	if err != nil {
-		return req, err
+		return 0, nil, err
 	}
 	defer httpRes.Body.Close()
 
-	bodyBytes, err := io.ReadAll(httpRes.Body)
-	if err != nil {
-		return req, err
+	var r io.Reader = httpRes.Body
+	if c.maxResponseSize > 0 {
+		if httpRes.ContentLength > c.maxResponseSize {
+			return httpRes.StatusCode, nil, ErrResponseTooLarge
+		}
+		r = &maxSizeReader{r: r, remaining: c.maxResponseSize}
+	}
+	if c.debug {
+		// Keep the raw body for logging.
+		respBody, err = io.ReadAll(r)
+		if err != nil {
+			return httpRes.StatusCode, nil, err
+		}
+		r = bytes.NewReader(respBody)
 	}
-	req.statusCode = httpRes.StatusCode
-	req.respBodyBytes = bodyBytes
-	if err := json.Unmarshal(bodyBytes, result); err != nil {
-		return req, err
+	if err := checkHTTPResponse(httpRes, r); err != nil {
+		if respBody == nil {
+			respBody = []byte(err.Body)
+		}
+		return httpRes.StatusCode, respBody, err
+	}
+	var head *headRecorder
+	if respBody == nil {
+		// Keep the beginning of the body for errors.
+		head = &headRecorder{r: r}
+		r = head
+	}
+	if err := decode(r); err != nil {
+		if head != nil {
+			head.fill()
+			respBody = head.head
+		}
+		return httpRes.StatusCode, respBody, err
 	}
-	return req, nil
+	return httpRes.StatusCode, respBody, nil
 }
 
 type rpcRequest struct {
@@ -322,11 +554,12 @@ type rpcRequest struct {
 	ID      uint64 `json:"id"`
 	Auth    any    `json:"auth,omitempty"`
 
+	authHeader    string `json:"-"`
 	statusCode    int    `json:"-"`
 	respBodyBytes []byte `json:"-"`
 }
 
-func (c *Client) newRPCRequest(method string, params any) *rpcRequest {
+func (c *Client) newRPCRequest(method string, params any, auth string, authInHeader bool) *rpcRequest {
 	reqID := c.requestID.Add(1)
 
 	r := &rpcRequest{
@@ -335,14 +568,18 @@ func (c *Client) newRPCRequest(method string, params any) *rpcRequest {
 		Params:  params,
 		ID:      reqID,
 	}
-	if c.auth != "" && method != loginMethod {
-		r.Auth = c.auth
+	if auth != "" && methodRequiresAuth(method) {
+		if authInHeader {
+			r.authHeader = auth
+		} else {
+			r.Auth = auth
+		}
 	}
 	return r
 }
 
-func (c *Client) newHTTPRequestWithContext(ctx context.Context, r *rpcRequest) (*http.Request, error) {
-	b, err := json.Marshal(r)
+func (c *Client) newHTTPRequestWithContext(ctx context.Context, body any, authHeader string) (*http.Request, error) {
+	b, err := json.Marshal(body)
 	if err != nil {
 		return nil, err
 	}
@@ -353,6 +590,12 @@ func (c *Client) newHTTPRequestWithContext(ctx context.Context, r *rpcRequest) (
 	if c.host != "" {
 		req.Host = c.host
 	}
+	for key, values := range c.header {
+		req.Header[key] = values
+	}
 	req.Header.Set("Content-Type", contentType)
+	if authHeader != "" {
+		req.Header.Set("Authorization", "Bearer "+authHeader)
+	}
 	return req, nil
 }
@@ -0,0 +1,270 @@
+package zabbix
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"golang.org/x/exp/slices"
+)
+
+type testRequest struct {
+	Method        string          `json:"method"`
+	Params        json.RawMessage `json:"params"`
+	ID            uint64          `json:"id"`
+	Auth          string          `json:"auth"`
+	Authorization string          `json:"-"`
+	Header        http.Header     `json:"-"`
+}
+
+// newTestServer starts a server which responds with apiVersion to
+// "apiinfo.version" and with the result of handler to other methods.
+// If handler returns an *APIError, it is sent as an error object.
+// Received requests are appended to *received. Requests are handled one at
+// a time.
+func newTestServer(t *testing.T, apiVersion string, received *[]testRequest,
+	handler func(req testRequest) any) *httptest.Server {
+	t.Helper()
+	var mu sync.Mutex
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		var req testRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("decode request: %v", err)
+			return
+		}
+		req.Authorization = r.Header.Get("Authorization")
+		req.Header = r.Header
+		*received = append(*received, req)
+
+		var result any
+		if req.Method == apiVersionMethod {
+			result = apiVersion
+		} else {
+			result = handler(req)
+		}
+		res := map[string]any{"jsonrpc": jsonrpcVersion, "id": req.ID}
+		if apiErr, ok := result.(*APIError); ok {
+			res["error"] = apiErr
+		} else {
+			res["result"] = result
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(res); err != nil {
+			t.Errorf("encode response: %v", err)
+		}
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func TestClientAuthMethod(t *testing.T) {
+	hostCount := func(testRequest) any { return "3" }
+
+	testCases := []struct {
+		name       string
+		apiVersion string
+		opts       []ClientOpt
+		wantHeader bool
+	}{
+		{name: "auto_6.0", apiVersion: "6.0.16", wantHeader: false},
+		{name: "auto_6.4", apiVersion: "6.4.0", wantHeader: true},
+		{name: "auto_7.2", apiVersion: "7.2.1", wantHeader: true},
+		{name: "field_7.0", apiVersion: "7.0.0",
+			opts: []ClientOpt{WithAuthMethod(AuthMethodField)}, wantHeader: false},
+		{name: "header_6.0", apiVersion: "6.0.16",
+			opts: []ClientOpt{WithAuthMethod(AuthMethodHeader)}, wantHeader: true},
+	}
+	for _, c := range testCases {
+		t.Run(c.name, func(t *testing.T) {
+			var received []testRequest
+			s := newTestServer(t, c.apiVersion, &received, hostCount)
+			opts := append([]ClientOpt{WithAPIToken("token1")}, c.opts...)
+			client, err := NewClient(s.URL, opts...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var count string
+			if err := client.Call(context.Background(), "host.get",
+				map[string]bool{"countOutput": true}, &count); err != nil {
+				t.Fatal(err)
+			}
+
+			for _, req := range received {
+				if req.Method == apiVersionMethod {
+					if req.Auth != "" || req.Authorization != "" {
+						t.Errorf("auth must not be sent for %s", apiVersionMethod)
+					}
+					continue
+				}
+				if c.wantHeader {
+					if got, want := req.Authorization, "Bearer token1"; got != want {
+						t.Errorf("authorization header mismatch, got=%q, want=%q", got, want)
+					}
+					if req.Auth != "" {
+						t.Errorf("auth field must be empty, got=%q", req.Auth)
+					}
+				} else {
+					if got, want := req.Auth, "token1"; got != want {
+						t.Errorf("auth field mismatch, got=%q, want=%q", got, want)
+					}
+					if req.Authorization != "" {
+						t.Errorf("authorization header must be empty, got=%q", req.Authorization)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestClientAPIVersionNotCachedOnError(t *testing.T) {
+	var mu sync.Mutex
+	var methods []string
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		var req testRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("decode request: %v", err)
+			return
+		}
+		methods = append(methods, req.Method)
+		if req.Method == apiVersionMethod && len(methods) == 1 {
+			http.Error(w, "service unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		var result any = "3"
+		if req.Method == apiVersionMethod {
+			result = "6.4.0"
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]any{"jsonrpc": jsonrpcVersion, "result": result, "id": req.ID})
+	}))
+	defer s.Close()
+
+	client, err := NewClient(s.URL, WithAPIToken("token1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	var count string
+	if err := client.Call(ctx, "host.get", map[string]bool{"countOutput": true}, &count); err == nil {
+		t.Fatal("want error for first call, got nil")
+	}
+	for i := 0; i < 2; i++ {
+		if err := client.Call(ctx, "host.get", map[string]bool{"countOutput": true}, &count); err != nil {
+			t.Fatal(err)
+		}
+	}
+	ver, err := client.APIVersion(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := ver.String(), "6.4.0"; got != want {
+		t.Errorf("API version mismatch, got=%s, want=%s", got, want)
+	}
+	want := []string{apiVersionMethod, apiVersionMethod, "host.get", "host.get"}
+	if !slices.Equal(methods, want) {
+		t.Errorf("methods mismatch, got=%v, want=%v", methods, want)
+	}
+}
+
+func TestClientReLogin(t *testing.T) {
+	sessionExpired := &APIError{
+		Code:    ErrorCodeInvalidParams,
+		Message: "Invalid params.",
+		Data:    "Session terminated, re-login, please.",
+	}
+
+	testCases := []struct {
+		name         string
+		reLogin      bool
+		wantErr      bool
+		wantSessions []string
+	}{
+		{name: "enabled", reLogin: true, wantErr: false,
+			wantSessions: []string{"session1", "session2"}},
+		{name: "disabled", reLogin: false, wantErr: true,
+			wantSessions: []string{"session1"}},
+	}
+	for _, c := range testCases {
+		t.Run(c.name, func(t *testing.T) {
+			var received []testRequest
+			var loginCount int
+			s := newTestServer(t, "6.0.16", &received, func(req testRequest) any {
+				switch req.Method {
+				case loginMethod:
+					loginCount++
+					return fmt.Sprintf("session%d", loginCount)
+				case "host.get":
+					if req.Auth == "session1" {
+						return sessionExpired
+					}
+					return "3"
+				}
+				return nil
+			})
+			client, err := NewClient(s.URL, WithReLogin(c.reLogin))
+			if err != nil {
+				t.Fatal(err)
+			}
+			ctx := context.Background()
+			if err := client.Login(ctx, "Admin", "zabbix"); err != nil {
+				t.Fatal(err)
+			}
+			var count string
+			err = client.Call(ctx, "host.get", map[string]bool{"countOutput": true}, &count)
+			if c.wantErr {
+				if !IsSessionExpired(err) {
+					t.Errorf("want session expired error, got=%v", err)
+				}
+			} else if err != nil {
+				t.Fatal(err)
+			}
+
+			var gotSessions []string
+			for _, req := range received {
+				if req.Method == "host.get" {
+					gotSessions = append(gotSessions, req.Auth)
+				}
+			}
+			if !slices.Equal(gotSessions, c.wantSessions) {
+				t.Errorf("sessions mismatch, got=%v, want=%v", gotSessions, c.wantSessions)
+			}
+		})
+	}
+}
+
+func TestClientWithAPIVersion(t *testing.T) {
+	var received []testRequest
+	s := newTestServer(t, "6.0.16", &received, func(req testRequest) any {
+		return "session1"
+	})
+	client, err := NewClient(s.URL, WithAPIVersion(MustParseAPIVersion("6.4.0")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	if err := client.Login(ctx, "Admin", "zabbix"); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := client.SessionID(), "session1"; got != want {
+		t.Errorf("session ID mismatch, got=%q, want=%q", got, want)
+	}
+	var methods []string
+	for _, req := range received {
+		methods = append(methods, req.Method)
+	}
+	if got, want := methods, []string{loginMethod}; !slices.Equal(got, want) {
+		t.Errorf("methods mismatch, got=%v, want=%v", got, want)
+	}
+	// The username parameter is used since 6.4.
+	if got, want := string(received[0].Params), `{"username":"Admin","password":"zabbix"}`; got != want {
+		t.Errorf("login params mismatch, got=%s, want=%s", got, want)
+	}
+}
@@ -0,0 +1,448 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/urfave/cli/v2"
+	"golang.org/x/exp/slices"
+	"gopkg.in/yaml.v3"
+
+	"github.com/hnakamur/go-zabbix/internal/outlog"
+	"github.com/hnakamur/go-zabbix/internal/slicex"
+)
+
+// maintenanceFile is the content of the file for "mainte apply" in YAML or
+// JSON. The values are written in the same format as the flags of
+// "mainte create".
+//
+//	managed-by: ops/maintenances
+//	maintenances:
+//	  - name: nightly backup
+//	    groups: [Databases]
+//	    include-nested: true
+//	    active-since: 2025-01-01T00:00
+//	    active-till: 2026-01-01T00:00
+//	    timeperiods:
+//	      - type: daily
+//	        start-time: "02:00"
+//	        period: 2h
+//	  - name: replace disks
+//	    type: no-data
+//	    hosts: [db1, db2]
+//	    timeperiods:
+//	      - start-date: 2025-03-01T10:00
+//	        period: 4h
+type maintenanceFile struct {
+	// ManagedBy is the marker added to the descriptions of the maintenances,
+	// which is used to find the ones to be deleted with "--prune".
+	ManagedBy    string            `yaml:"managed-by"`
+	Maintenances []maintenanceSpec `yaml:"maintenances"`
+}
+
+type maintenanceSpec struct {
+	Name          string   `yaml:"name"`
+	Description   string   `yaml:"description"`
+	Type          string   `yaml:"type"`
+	ActiveSince   string   `yaml:"active-since"`
+	ActiveTill    string   `yaml:"active-till"`
+	Hosts         []string `yaml:"hosts"`
+	Groups        []string `yaml:"groups"`
+	IncludeNested bool     `yaml:"include-nested"`
+	Tags          []string `yaml:"tags"`
+	TagsEvalType  string   `yaml:"tags-evaltype"`
+	// TimePeriods is named after the property of Zabbix API and the flags.
+	TimePeriods []timePeriodSpec `yaml:"timeperiods"`
+}
+
+type timePeriodSpec struct {
+	Type      string        `yaml:"type"`
+	Period    time.Duration `yaml:"period"`
+	StartDate string        `yaml:"start-date"`
+	StartTime string        `yaml:"start-time"`
+	Every     int           `yaml:"every"`
+	DayOfWeek string        `yaml:"day-of-week"`
+	Day       int           `yaml:"day"`
+	Month     string        `yaml:"month"`
+}
+
+func loadMaintenanceFile(cCtx *cli.Context) (*maintenanceFile, error) {
+	path := cCtx.String("file")
+	var data []byte
+	var err error
+	if path == "-" {
+		data, err = io.ReadAll(cCtx.App.Reader)
+	} else {
+		data, err = os.ReadFile(path)
+	}
+	if err != nil {
+		return nil, err
+	}
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	// Report typos of keys.
+	dec.KnownFields(true)
+	var f maintenanceFile
+	if err := dec.Decode(&f); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("parse maintenance file %s: %w", path, err)
+	}
+	return &f, nil
+}
+
+// toMaintenance returns the maintenance of s without hosts and host groups,
+// since they need to be looked up.
+func (s maintenanceSpec) toMaintenance(managedBy string) (*Maintenance, error) {
+	if s.Name == "" {
+		return nil, errors.New(`"name" must be set`)
+	}
+	if len(s.Hosts) == 0 && len(s.Groups) == 0 {
+		return nil, errors.New(`at least "hosts" or "groups" must be set`)
+	}
+	if len(s.TimePeriods) == 0 {
+		return nil, errors.New(`"timeperiods" must be set`)
+	}
+
+	m := &Maintenance{
+		Name:            s.Name,
+		Description:     addManagedByMarker(s.Description, managedBy),
+		MaintenanceType: MaintenanceTypeWithData,
+		TagsEvalType:    TagsEvalTypeAndOr,
+		TimePeriods:     make([]TimePeriod, len(s.TimePeriods)),
+		Tags:            []ProblemTag{},
+	}
+	if s.Type != "" {
+		var v maintenanceTypeValue
+		if err := v.Set(s.Type); err != nil {
+			return nil, err
+		}
+		m.MaintenanceType = v.typ
+	}
+	if s.TagsEvalType != "" {
+		var v tagsEvalTypeValue
+		if err := v.Set(s.TagsEvalType); err != nil {
+			return nil, err
+		}
+		m.TagsEvalType = v.evalType
+	}
+	if len(s.Tags) > 0 {
+		if m.MaintenanceType != MaintenanceTypeWithData {
+			return nil, errors.New(`"tags" can be set only for maintenance with data collection`)
+		}
+		tags, err := parseTags(s.Tags)
+		if err != nil {
+			return nil, err
+		}
+		m.Tags = tags
+	}
+
+	recurring := false
+	for i, spec := range s.TimePeriods {
+		tp, err := spec.toTimePeriod()
+		if err != nil {
+			return nil, fmt.Errorf("timeperiods[%d]: %w", i, err)
+		}
+		m.TimePeriods[i] = tp
+		if tp.TimeperiodType != TimeperiodTypeOnetimeOnly {
+			recurring = true
+		}
+	}
+
+	// The active period defaults to the one covering all one-time time
+	// periods, so that the same file always results in the same maintenance.
+	if s.ActiveSince != "" {
+		t, err := parseLocalTime(s.ActiveSince)
+		if err != nil {
+			return nil, fmt.Errorf(`invalid "active-since": %w`, err)
+		}
+		m.ActiveSince = t
+	} else if recurring {
+		return nil, errors.New(`"active-since" must be set for recurring time periods`)
+	} else {
+		for i, tp := range m.TimePeriods {
+			if i == 0 || tp.StartDate.Before(m.ActiveSince) {
+				m.ActiveSince = tp.StartDate
+			}
+		}
+	}
+	if s.ActiveTill != "" {
+		t, err := parseLocalTime(s.ActiveTill)
+		if err != nil {
+			return nil, fmt.Errorf(`invalid "active-till": %w`, err)
+		}
+		m.ActiveTill = t
+	} else if recurring {
+		return nil, errors.New(`"active-till" must be set for recurring time periods`)
+	} else {
+		for _, tp := range m.TimePeriods {
+			if end := tp.StartDate.Add(tp.Period); end.After(m.ActiveTill) {
+				m.ActiveTill = end
+			}
+		}
+	}
+	return m, nil
+}
+
+func (s timePeriodSpec) toTimePeriod() (TimePeriod, error) {
+	if s.Period == 0 {
+		return TimePeriod{}, errors.New(`"period" must be set`)
+	}
+	tp := TimePeriod{
+		Period:         s.Period,
+		TimeperiodType: TimeperiodTypeOnetimeOnly,
+		Every:          s.Every,
+		Day:            s.Day,
+	}
+	if s.Type != "" {
+		var v timeperiodTypeValue
+		if err := v.Set(s.Type); err != nil {
+			return TimePeriod{}, err
+		}
+		tp.TimeperiodType = v.typ
+	}
+
+	if tp.TimeperiodType == TimeperiodTypeOnetimeOnly {
+		if s.StartDate == "" {
+			return TimePeriod{}, errors.New(`"start-date" must be set for one-time time periods`)
+		}
+		if s.StartTime != "" || s.Every != 0 || s.DayOfWeek != "" || s.Day != 0 || s.Month != "" {
+			return TimePeriod{}, errors.New(`only "start-date" and "period" can be set for one-time time periods`)
+		}
+		t, err := parseLocalTime(s.StartDate)
+		if err != nil {
+			return TimePeriod{}, fmt.Errorf(`invalid "start-date": %w`, err)
+		}
+		tp.StartDate = t
+		return tp, nil
+	}
+
+	if s.StartDate != "" {
+		return TimePeriod{}, errors.New(`"start-date" can be set only for one-time time periods`)
+	}
+	if s.StartTime != "" {
+		var v timeOfDayValue
+		if err := v.Set(s.StartTime); err != nil {
+			return TimePeriod{}, err
+		}
+		tp.StartTime = v.d
+	}
+	if s.DayOfWeek != "" {
+		var v dayOfWeekValue
+		if err := v.Set(s.DayOfWeek); err != nil {
+			return TimePeriod{}, err
+		}
+		tp.DayOfWeek = v.days
+	}
+	if s.Month != "" {
+		var v monthValue
+		if err := v.Set(s.Month); err != nil {
+			return TimePeriod{}, err
+		}
+		tp.Month = v.months
+	}
+	if err := validateRecurringTimePeriod(&tp); err != nil {
+		return TimePeriod{}, err
+	}
+	return tp, nil
+}
+
+func parseLocalTime(s string) (time.Time, error) {
+	return time.ParseInLocation(timeFormatRFC3339Minute, s, time.Local)
+}
+
+// managedByMarker returns the last line of the descriptions of maintenances
+// managed with the file whose "managed-by" is managedBy.
+func managedByMarker(managedBy string) string {
+	return "managed-by: " + managedBy
+}
+
+func addManagedByMarker(description, managedBy string) string {
+	if managedBy == "" {
+		return description
+	}
+	if description == "" {
+		return managedByMarker(managedBy)
+	}
+	return description + "\n\n" + managedByMarker(managedBy)
+}
+
+func hasManagedByMarker(description, managedBy string) bool {
+	lines := strings.Split(description, "\n")
+	return lines[len(lines)-1] == managedByMarker(managedBy)
+}
+
+func applyMaintenancesAction(cCtx *cli.Context) error {
+	file, err := loadMaintenanceFile(cCtx)
+	if err != nil {
+		return err
+	}
+	prune := cCtx.Bool("prune")
+	if prune && file.ManagedBy == "" {
+		return errors.New(`"managed-by" must be set in the file to use "--prune"`)
+	}
+
+	desired := make([]*Maintenance, len(file.Maintenances))
+	names := make(map[string]bool)
+	for i, spec := range file.Maintenances {
+		m, err := spec.toMaintenance(file.ManagedBy)
+		if err != nil {
+			return fmt.Errorf("maintenances[%d] %q: %w", i, spec.Name, err)
+		}
+		if names[m.Name] {
+			return fmt.Errorf("duplicated maintenance name: %q", m.Name)
+		}
+		names[m.Name] = true
+		desired[i] = m
+	}
+
+	client, err := newClient(cCtx)
+	if err != nil {
+		return err
+	}
+	defer client.close(cCtx.Context)
+
+	existing, err := client.GetMaintenances(cCtx.Context)
+	if err != nil {
+		return err
+	}
+	existingByName := make(map[string]*Maintenance, len(existing))
+	for i := range existing {
+		existingByName[existing[i].Name] = &existing[i]
+	}
+
+	for i, m := range desired {
+		spec := file.Maintenances[i]
+		m.Hosts, err = getHostsJustID(cCtx, client, spec.Hosts)
+		if err != nil {
+			return err
+		}
+		m.Groups, err = getHostGroupsJustID(cCtx, client, spec.Groups, spec.IncludeNested)
+		if err != nil {
+			return err
+		}
+
+		cur, ok := existingByName[m.Name]
+		if !ok {
+			if cCtx.Bool("dry-run") {
+				outlog.Info("skip creating maintenance due to dry run", "name", m.Name)
+				continue
+			}
+			if err := client.CreateMaintenance(cCtx.Context, m); err != nil {
+				return fmt.Errorf("create maintenance %q: %w", m.Name, err)
+			}
+			outlog.Info("created maintenance", "name", m.Name, "maintenance_id", m.MaintenanceID)
+			continue
+		}
+
+		m.MaintenanceID = cur.MaintenanceID
+		if maintenanceEqual(cur, m) {
+			outlog.Info("maintenance is up to date", "name", m.Name, "maintenance_id", m.MaintenanceID)
+			continue
+		}
+		if cCtx.Bool("dry-run") {
+			outlog.Info("skip updating maintenance due to dry run", "name", m.Name, "maintenance_id", m.MaintenanceID)
+			continue
+		}
+		if err := client.UpdateMaintenance(cCtx.Context, m); err != nil {
+			return fmt.Errorf("update maintenance %q: %w", m.Name, err)
+		}
+		outlog.Info("updated maintenance", "name", m.Name, "maintenance_id", m.MaintenanceID)
+	}
+
+	if !prune {
+		return nil
+	}
+	var pruneIDs []string
+	for _, m := range existing {
+		if !names[m.Name] && hasManagedByMarker(m.Description, file.ManagedBy) {
+			pruneIDs = append(pruneIDs, m.MaintenanceID)
+		}
+	}
+	if len(pruneIDs) == 0 {
+		return nil
+	}
+	if cCtx.Bool("dry-run") {
+		outlog.Info("skip deleting maintenance due to dry run", "ids", pruneIDs)
+		return nil
+	}
+	deletedIDs, err := client.DeleteMaintenancesByIDs(cCtx.Context, pruneIDs)
+	if err != nil {
+		return err
+	}
+	outlog.Info("deleted maintenances", "target_ids", pruneIDs, "deleted_ids", deletedIDs)
+	return nil
+}
+
+// maintenanceEqual returns whether the properties set by "mainte apply" are
+// the same in a and b.
+func maintenanceEqual(a, b *Maintenance) bool {
+	return reflect.DeepEqual(normalizeMaintenance(a), normalizeMaintenance(b))
+}
+
+// normalizeMaintenance returns a copy of m with only the properties set by
+// "mainte apply", in a form which does not depend on the order of elements,
+// time zones and values the API fills for unused properties.
+func normalizeMaintenance(m *Maintenance) Maintenance {
+	n := Maintenance{
+		Name:            m.Name,
+		ActiveSince:     time.Unix(m.ActiveSince.Unix(), 0),
+		ActiveTill:      time.Unix(m.ActiveTill.Unix(), 0),
+		Description:     m.Description,
+		MaintenanceType: m.MaintenanceType,
+		TagsEvalType:    m.TagsEvalType,
+		Groups: slicex.Map(m.Groups, func(g HostGroup) HostGroup {
+			return HostGroup{GroupID: g.GroupID}
+		}),
+		Hosts: slicex.Map(m.Hosts, func(h Host) Host {
+			return Host{HostID: h.HostID}
+		}),
+		TimePeriods: slicex.Map(m.TimePeriods, normalizeTimePeriod),
+		Tags:        append([]ProblemTag{}, m.Tags...),
+	}
+	slices.SortFunc(n.Groups, func(a, b HostGroup) bool { return a.GroupID < b.GroupID })
+	slices.SortFunc(n.Hosts, func(a, b Host) bool { return a.HostID < b.HostID })
+	slices.SortFunc(n.Tags, func(a, b ProblemTag) bool { return formatTag(a) < formatTag(b) })
+	slices.SortFunc(n.TimePeriods, func(a, b TimePeriod) bool {
+		return timePeriodKey(a) < timePeriodKey(b)
+	})
+	return n
+}
+
+func normalizeTimePeriod(tp TimePeriod) TimePeriod {
+	n := TimePeriod{
+		Period:         tp.Period,
+		TimeperiodType: tp.TimeperiodType,
+	}
+	if tp.TimeperiodType == TimeperiodTypeOnetimeOnly {
+		n.StartDate = time.Unix(tp.StartDate.Unix(), 0)
+		return n
+	}
+
+	n.StartTime = tp.StartTime
+	n.Every = tp.Every
+	switch tp.TimeperiodType {
+	case TimeperiodTypeWeekly:
+		n.DayOfWeek = tp.DayOfWeek
+	case TimeperiodTypeMonthly:
+		n.DayOfWeek = tp.DayOfWeek
+		n.Day = tp.Day
+		n.Month = tp.Month
+	}
+	// "every" is not used for monthly time periods with "day", and defaults
+	// to 1 for the others.
+	if n.TimeperiodType == TimeperiodTypeMonthly && n.DayOfWeek == 0 {
+		n.Every = 0
+	} else if n.Every == 0 {
+		n.Every = 1
+	}
+	return n
+}
+
+// timePeriodKey returns a string to sort normalized time periods.
+func timePeriodKey(tp TimePeriod) string {
+	return fmt.Sprintf("%s %d %d %+v", tp.TimeperiodType, tp.StartDate.Unix(), tp.Period, tp)
+}
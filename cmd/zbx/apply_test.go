@@ -0,0 +1,202 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"golang.org/x/exp/slices"
+
+	"github.com/hnakamur/go-zabbix"
+	"github.com/hnakamur/go-zabbix/maintenance"
+	"github.com/hnakamur/go-zabbix/zabbixtest"
+)
+
+const applyTestFile = `managed-by: test
+maintenances:
+  - name: nightly backup
+    groups: [Linux servers]
+    active-since: 2030-01-01T00:00
+    active-till: 2031-01-01T00:00
+    timeperiods:
+      - type: daily
+        start-time: "02:00"
+        period: 2h
+      - type: weekly
+        day-of-week: sat,sun
+        period: 1h
+      - type: monthly
+        day: 1
+        month: all
+        period: 30m
+  - name: replace disks
+    type: no-data
+    hosts: [server1]
+    timeperiods:
+      - start-date: 2030-03-01T10:00
+        period: 4h
+      - start-date: 2030-03-02T10:00
+        period: 4h
+`
+
+func TestApplyMaintenances(t *testing.T) {
+	testCases := []struct {
+		name string
+		// setup is the arguments of the commands run before "mainte apply".
+		setup [][]string
+		// file is the file for "mainte apply".
+		file       string
+		globalArgs []string
+		args       []string
+		// wantWrites is the methods which modify maintenances sent by
+		// "mainte apply".
+		wantWrites []string
+		wantOutput string
+		// wantNames is the names of the maintenances after "mainte apply".
+		wantNames []string
+	}{
+		{
+			name:       "create",
+			file:       applyTestFile,
+			wantWrites: []string{"maintenance.create", "maintenance.create"},
+			wantNames:  []string{"nightly backup", "replace disks"},
+		},
+		{
+			// The defaults of "every", "active-since" and "active-till" must
+			// not cause differences.
+			name:       "upToDate",
+			setup:      [][]string{{"mainte", "apply", "-f", "FILE"}},
+			file:       applyTestFile,
+			wantWrites: nil,
+			wantOutput: "maintenance is up to date",
+			wantNames:  []string{"nightly backup", "replace disks"},
+		},
+		{
+			name:  "update",
+			setup: [][]string{{"mainte", "apply", "-f", "FILE"}},
+			file: strings.Replace(applyTestFile, `start-time: "02:00"`,
+				`start-time: "03:00"`, 1),
+			wantWrites: []string{"maintenance.update"},
+			wantNames:  []string{"nightly backup", "replace disks"},
+		},
+		{
+			name: "prune",
+			setup: [][]string{
+				{"mainte", "apply", "-f", "FILE"},
+				{"mainte", "create", "-n", "unmanaged", "-H", "server1", "-p", "1h"},
+				{"mainte", "create", "-n", "other", "-H", "server1", "-p", "1h",
+					"-d", "managed-by: other"},
+				{"mainte", "create", "-n", "marker not at end", "-H", "server1", "-p", "1h",
+					"-d", "managed-by: test\n\nnote"},
+			},
+			file:       applyTestFile[:strings.Index(applyTestFile, "  - name: replace disks")],
+			args:       []string{"--prune"},
+			wantWrites: []string{"maintenance.delete"},
+			wantNames:  []string{"marker not at end", "nightly backup", "other", "unmanaged"},
+		},
+		{
+			name:  "dryRun",
+			setup: [][]string{{"mainte", "apply", "-f", "FILE"}},
+			file: strings.Replace(applyTestFile, "  - name: replace disks", "  - name: new disks", 1) +
+				"  - name: another\n    hosts: [server2]\n    timeperiods:\n" +
+				"      - start-date: 2030-04-01T10:00\n        period: 1h\n",
+			globalArgs: []string{"--dry-run"},
+			args:       []string{"--prune"},
+			wantWrites: nil,
+			wantOutput: "due to dry run",
+			wantNames:  []string{"nightly backup", "replace disks"},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			unsetZBXEnv(t)
+			s := zabbixtest.NewServer(zabbixtest.WithAPIToken("token1"))
+			defer s.Close()
+			groupID := s.AddHostGroup("Linux servers")
+			s.AddHost("server1", groupID)
+			s.AddHost("server2", groupID)
+
+			dir := t.TempDir()
+			setupFile := filepath.Join(dir, "setup.yaml")
+			if err := os.WriteFile(setupFile, []byte(applyTestFile), 0o600); err != nil {
+				t.Fatal(err)
+			}
+			file := filepath.Join(dir, "maintenances.yaml")
+			if err := os.WriteFile(file, []byte(tc.file), 0o600); err != nil {
+				t.Fatal(err)
+			}
+
+			for _, args := range tc.setup {
+				args = slices.Clone(args)
+				if i := slices.Index(args, "FILE"); i != -1 {
+					args[i] = setupFile
+				}
+				if _, err := runZBX(s, args...); err != nil {
+					t.Fatalf("setup %v: %v", args, err)
+				}
+			}
+
+			n := len(s.Requests())
+			args := append(slices.Clone(tc.globalArgs), "mainte", "apply", "-f", file)
+			output, err := runZBX(s, append(args, tc.args...)...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var writes []string
+			for _, req := range s.Requests()[n:] {
+				switch req.Method {
+				case "maintenance.create", "maintenance.update", "maintenance.delete":
+					writes = append(writes, req.Method)
+				}
+			}
+			if !slices.Equal(writes, tc.wantWrites) {
+				t.Errorf("writes mismatch, got=%v, want=%v", writes, tc.wantWrites)
+			}
+			if !strings.Contains(output, tc.wantOutput) {
+				t.Errorf("output must contain %q, got=%s", tc.wantOutput, output)
+			}
+
+			client, err := zabbix.NewClient(s.URL, zabbix.WithAPIToken("token1"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			ms, err := maintenance.GetAll(context.Background(), client)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var names []string
+			for _, m := range ms {
+				names = append(names, m.Name)
+			}
+			slices.Sort(names)
+			if !slices.Equal(names, tc.wantNames) {
+				t.Errorf("names mismatch, got=%v, want=%v", names, tc.wantNames)
+			}
+		})
+	}
+}
+
+// runZBX runs zbx with args for s with the API token "token1", and returns
+// the output.
+func runZBX(s *zabbixtest.Server, args ...string) (string, error) {
+	return runApp(append([]string{"--url", s.URL, "--token", "token1"}, args...)...)
+}
+
+// runApp runs zbx with args without a config file, and returns the output.
+func runApp(args ...string) (string, error) {
+	return runAppContext(context.Background(), args...)
+}
+
+// runAppContext is like runApp but runs zbx with ctx.
+func runAppContext(ctx context.Context, args ...string) (string, error) {
+	var out bytes.Buffer
+	app := newApp()
+	app.Writer = &out
+	app.ErrWriter = &out
+	args = append([]string{"zbx", "--config", os.DevNull}, args...)
+	err := app.RunContext(ctx, args)
+	return out.String(), err
+}
@@ -1,7 +1,36 @@
 package main
 
-import "github.com/hnakamur/go-zabbix/internal/rpc"
+import (
+	"context"
+
+	"github.com/hnakamur/go-zabbix"
+	"github.com/hnakamur/go-zabbix/internal/errlog"
+)
 
 type myClient struct {
-	inner *rpc.Client
+	inner *zabbix.Client
+
+	// loggedIn is true if the client logged in with a username and a password
+	// or resumed a cached session, instead of using an API token.
+	loggedIn bool
+	// sessionCache is nil unless "--session-cache" is set.
+	sessionCache *sessionCache
+}
+
+// close saves the session to the cache if it is enabled, or terminates the
+// session otherwise, so that no session is left in the server. It is called
+// even if the command failed or was interrupted, so the error is only logged.
+func (c *myClient) close(ctx context.Context) {
+	if !c.loggedIn {
+		return
+	}
+	ctx = context.WithoutCancel(ctx)
+	if c.sessionCache != nil {
+		if err := c.saveSession(ctx); err != nil {
+			errlog.Warn("failed to save session", "err", err)
+		}
+	} else if err := c.inner.Logout(ctx); err != nil {
+		errlog.Warn("failed to log out", "err", err)
+	}
+	c.loggedIn = false
 }
@@ -0,0 +1,223 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/urfave/cli/v2"
+	"golang.org/x/exp/slices"
+	"gopkg.in/yaml.v3"
+)
+
+// config is the content of the config file.
+//
+//	default-profile: staging
+//	profiles:
+//	  staging:
+//	    url: https://zabbix-staging.example.com/zabbix
+//	    token-file: ~/.config/zbx/staging.token
+//	  production:
+//	    url: https://zabbix.example.com/zabbix
+//	    virtual-host: zabbix.example.com
+//	    username: Admin
+//	    password-command: pass show zabbix/production
+//	    ca-cert: /etc/ssl/certs/internal-ca.pem
+//	    log-format: json
+type config struct {
+	DefaultProfile string              `yaml:"default-profile"`
+	Profiles       map[string]*profile `yaml:"profiles"`
+}
+
+// profile has values for the global flags with the same names.
+// Secrets cannot be written directly; use token-file, password-command or
+// credential-store instead.
+type profile struct {
+	URL                string        `yaml:"url"`
+	VirtualHost        string        `yaml:"virtual-host"`
+	Username           string        `yaml:"username"`
+	PasswordCommand    string        `yaml:"password-command"`
+	TokenFile          string        `yaml:"token-file"`
+	CredentialStore    string        `yaml:"credential-store"`
+	SessionCache       string        `yaml:"session-cache"`
+	CACert             string        `yaml:"ca-cert"`
+	ClientCert         string        `yaml:"client-cert"`
+	ClientKey          string        `yaml:"client-key"`
+	InsecureSkipVerify bool          `yaml:"insecure-skip-verify"`
+	Proxy              string        `yaml:"proxy"`
+	Header             []string      `yaml:"header"`
+	BasicAuthUser      string        `yaml:"basic-auth-user"`
+	Timeout            time.Duration `yaml:"timeout"`
+	RateLimit          float64       `yaml:"rate-limit"`
+	// LogFlags is a pointer since an empty string is a valid value.
+	LogFlags  *string `yaml:"log-flags"`
+	LogFormat string  `yaml:"log-format"`
+}
+
+// defaultConfigPath returns the path of the config file used if "--config"
+// is not set, e.g. ~/.config/zbx/config.yaml on Linux.
+func defaultConfigPath() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "zbx", "config.yaml")
+}
+
+func loadConfig(path string) (*config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	// Report typos of keys.
+	dec.KnownFields(true)
+	var cfg config
+	if err := dec.Decode(&cfg); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("parse config file %s: %w", path, err)
+	}
+	return &cfg, nil
+}
+
+// applyProfile sets the global flags to the values in the profile selected
+// with "--profile" or "default-profile" in the config file. Flags which are
+// set in the command line or environment variables are left untouched, and
+// the credentials in the profile are not used if any credentials are set.
+func applyProfile(cCtx *cli.Context) error {
+	path := cCtx.String("config")
+	cfg, err := loadConfig(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) && !cCtx.IsSet("config") {
+			if cCtx.IsSet("profile") {
+				return fmt.Errorf("config file not found for profile %q: %s",
+					cCtx.String("profile"), path)
+			}
+			return nil
+		}
+		return err
+	}
+
+	name := cCtx.String("profile")
+	if name == "" {
+		name = cfg.DefaultProfile
+	}
+	if name == "" {
+		return nil
+	}
+	p, ok := cfg.Profiles[name]
+	if !ok || p == nil {
+		return fmt.Errorf("profile %q not found in config file %s", name, path)
+	}
+
+	values := []profileValue{
+		{"url", stringValues(p.URL)},
+		{"virtual-host", stringValues(p.VirtualHost)},
+		{"username", stringValues(p.Username)},
+		{"password-command", stringValues(p.PasswordCommand)},
+		{"token-file", stringValues(expandHome(p.TokenFile))},
+		{"credential-store", stringValues(expandHome(p.CredentialStore))},
+		{"session-cache", stringValues(expandHome(p.SessionCache))},
+		{"ca-cert", stringValues(expandHome(p.CACert))},
+		{"client-cert", stringValues(expandHome(p.ClientCert))},
+		{"client-key", stringValues(expandHome(p.ClientKey))},
+		{"proxy", stringValues(p.Proxy)},
+		{"header", p.Header},
+		{"basic-auth-user", stringValues(p.BasicAuthUser)},
+		{"log-format", stringValues(p.LogFormat)},
+	}
+	if p.InsecureSkipVerify {
+		values = append(values, profileValue{"insecure-skip-verify", []string{"true"}})
+	}
+	if p.Timeout != 0 {
+		values = append(values, profileValue{"timeout", []string{p.Timeout.String()}})
+	}
+	if p.RateLimit != 0 {
+		values = append(values, profileValue{"rate-limit",
+			[]string{strconv.FormatFloat(p.RateLimit, 'g', -1, 64)}})
+	}
+	if p.LogFlags != nil {
+		values = append(values, profileValue{"log-flags", []string{*p.LogFlags}})
+	}
+
+	// The credentials in the profile are overridden field by field, but
+	// the ones for another source than the flags or the environment variables
+	// select are not used, e.g. "--username" must not be ignored because of
+	// "token-file" in the profile, while "--password" is used with "username"
+	// in the profile.
+	// The flags are checked before setting any values in the profile.
+	credentialSet := make(map[string]bool)
+	for _, flags := range credentialSources {
+		for _, flag := range flags {
+			credentialSet[flag] = cCtx.IsSet(flag)
+		}
+	}
+	for _, v := range values {
+		if len(v.values) == 0 || cCtx.IsSet(v.flag) {
+			continue
+		}
+		if otherCredentialSourceSet(credentialSet, v.flag) {
+			continue
+		}
+		for _, value := range v.values {
+			if err := cCtx.Set(v.flag, value); err != nil {
+				return fmt.Errorf("invalid %q in profile %q: %w", v.flag, name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// credentialSources is the groups of the flags which specify the credentials
+// for each source.
+var credentialSources = [][]string{
+	{"token", "token-file"},
+	{"username", "password", "password-command"},
+	{"credential-store"},
+}
+
+// otherCredentialSourceSet returns whether flag is one for the credentials,
+// and a flag for another source of the credentials is in set.
+func otherCredentialSourceSet(set map[string]bool, flag string) bool {
+	i := slices.IndexFunc(credentialSources, func(flags []string) bool {
+		return slices.Contains(flags, flag)
+	})
+	if i == -1 {
+		return false
+	}
+	for j, flags := range credentialSources {
+		if j != i && slices.ContainsFunc(flags, func(f string) bool { return set[f] }) {
+			return true
+		}
+	}
+	return false
+}
+
+// profileValue is values in a profile for a flag.
+type profileValue struct {
+	flag   string
+	values []string
+}
+
+func stringValues(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return []string{s}
+}
+
+// expandHome replaces the leading "~/" in path with the home directory.
+func expandHome(path string) string {
+	if rest, ok := strings.CutPrefix(path, "~/"); ok {
+		if home, err := os.UserHomeDir(); err == nil {
+			return filepath.Join(home, rest)
+		}
+	}
+	return path
+}
@@ -0,0 +1,124 @@
+package main
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/hnakamur/go-zabbix"
+)
+
+// unsetZBXEnv unsets the environment variables for the flags during the test,
+// so that the environment running the test does not affect it.
+func unsetZBXEnv(t *testing.T) {
+	t.Helper()
+	for _, env := range os.Environ() {
+		name, _, _ := strings.Cut(env, "=")
+		if strings.HasPrefix(name, "ZBX_") {
+			t.Setenv(name, "")
+			os.Unsetenv(name)
+		}
+	}
+}
+
+func TestApplyProfileCredentials(t *testing.T) {
+	dir := t.TempDir()
+	tokenFile := filepath.Join(dir, "token")
+	if err := os.WriteFile(tokenFile, []byte("profile-token\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	configFile := filepath.Join(dir, "config.yaml")
+	config := "default-profile: test\n" +
+		"profiles:\n" +
+		"  test:\n" +
+		"    url: http://zabbix.example.com\n" +
+		"    username: profile-user\n" +
+		"    token-file: " + tokenFile + "\n"
+	if err := os.WriteFile(configFile, []byte(config), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		name string
+		args []string
+		env  map[string]string
+		want zabbix.Credentials
+	}{
+		{
+			name: "profile",
+			want: zabbix.Credentials{APIToken: "profile-token"},
+		},
+		{
+			name: "flags",
+			args: []string{"--username", "flag-user", "--password", "flag-password"},
+			want: zabbix.Credentials{Username: "flag-user", Password: "flag-password"},
+		},
+		{
+			name: "envVars",
+			env:  map[string]string{"ZBX_USERNAME": "env-user", "ZBX_PASSWORD": "env-password"},
+			want: zabbix.Credentials{Username: "env-user", Password: "env-password"},
+		},
+		{
+			name: "tokenFlag",
+			args: []string{"--token", "flag-token"},
+			want: zabbix.Credentials{APIToken: "flag-token"},
+		},
+		{
+			// The username in the profile is used with the password in the
+			// flag, while the token file in the profile is not used.
+			name: "passwordFlag",
+			args: []string{"--password", "flag-password"},
+			want: zabbix.Credentials{Username: "profile-user", Password: "flag-password"},
+		},
+		{
+			name: "passwordEnvVar",
+			env:  map[string]string{"ZBX_PASSWORD": "env-password"},
+			want: zabbix.Credentials{Username: "profile-user", Password: "env-password"},
+		},
+		{
+			name: "passwordCommandFlag",
+			args: []string{"--password-command", "echo command-password"},
+			want: zabbix.Credentials{Username: "profile-user", Password: "command-password"},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			unsetZBXEnv(t)
+			for name, value := range tc.env {
+				t.Setenv(name, value)
+			}
+
+			var got zabbix.Credentials
+			app := newApp()
+			app.Writer = io.Discard
+			app.ErrWriter = io.Discard
+			app.Commands = []*cli.Command{
+				{
+					Name: "test",
+					Action: func(cCtx *cli.Context) error {
+						provider, err := credentialsProvider(cCtx)
+						if err != nil {
+							// No credentials are set.
+							return nil
+						}
+						got, err = provider.Credentials(cCtx.Context)
+						return err
+					},
+				},
+			}
+			args := append([]string{"zbx", "--config", configFile}, tc.args...)
+			args = append(args, "test")
+			if err := app.RunContext(context.Background(), args); err != nil {
+				t.Fatal(err)
+			}
+			if got != tc.want {
+				t.Errorf("credentials mismatch, got=%+v, want=%+v", got, tc.want)
+			}
+		})
+	}
+}
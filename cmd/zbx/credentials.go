@@ -0,0 +1,146 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io/fs"
+	"os"
+	"strings"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/hnakamur/go-zabbix"
+	"github.com/hnakamur/go-zabbix/credentials"
+	"github.com/hnakamur/go-zabbix/internal/outlog"
+)
+
+// credentialsProvider returns the provider of credentials specified with the
+// global flags. The first one set in the following order is used: "--token",
+// "--token-file", "--password", "--password-command", "--credential-store"
+// and the prompt for the password of "--username".
+func credentialsProvider(cCtx *cli.Context) (zabbix.CredentialsProvider, error) {
+	if token := cCtx.String("token"); token != "" {
+		return credentials.Static(zabbix.Credentials{APIToken: token}), nil
+	}
+	if path := cCtx.String("token-file"); path != "" {
+		return credentials.TokenFile(path), nil
+	}
+
+	username := cCtx.String("username")
+	if username != "" {
+		if password := cCtx.String("password"); password != "" {
+			return credentials.Static(zabbix.Credentials{Username: username, Password: password}), nil
+		}
+		if command := cCtx.String("password-command"); command != "" {
+			return credentials.PasswordCommand(username, command), nil
+		}
+	}
+	if path := cCtx.String("credential-store"); path != "" {
+		return newCredentialStore(path).Provider(credentialStoreKey(cCtx)), nil
+	}
+	if username == "" {
+		return nil, errors.New(`"--token", "--token-file", "--credential-store" or "--username" must be set`)
+	}
+
+	// Prompt only when the credentials are needed, e.g. not when a cached
+	// session is valid, and only once.
+	var password []byte
+	return zabbix.CredentialsFunc(func(ctx context.Context) (zabbix.Credentials, error) {
+		if password == nil {
+			p, err := readSecret("Enter password for Zabbix:")
+			if err != nil {
+				return zabbix.Credentials{}, err
+			}
+			password = p
+		}
+		return zabbix.Credentials{Username: username, Password: string(password)}, nil
+	}), nil
+}
+
+func newCredentialStore(path string) *credentials.Store {
+	return credentials.NewStore(path, func() ([]byte, error) {
+		return readSecret("Enter passphrase for credential store:")
+	})
+}
+
+// credentialStoreKey returns the key of credentials for the Zabbix URL.
+func credentialStoreKey(cCtx *cli.Context) string {
+	return strings.TrimSuffix(cCtx.String("url"), "/")
+}
+
+func setCredentialAction(cCtx *cli.Context) error {
+	if _, err := requireURL(cCtx); err != nil {
+		return err
+	}
+	path := cCtx.String("credential-store")
+	if path == "" {
+		return errors.New(`"--credential-store" must be set`)
+	}
+
+	var store *credentials.Store
+	if _, err := os.Stat(path); errors.Is(err, fs.ErrNotExist) {
+		passphrase, err := readNewPassphrase()
+		if err != nil {
+			return err
+		}
+		store = credentials.NewStore(path, func() ([]byte, error) { return passphrase, nil })
+	} else {
+		store = newCredentialStore(path)
+	}
+
+	var creds zabbix.Credentials
+	if username := cCtx.String("username"); username != "" {
+		password, err := readSecret("Enter password for Zabbix:")
+		if err != nil {
+			return err
+		}
+		creds = zabbix.Credentials{Username: username, Password: string(password)}
+	} else {
+		token, err := readSecret("Enter API token for Zabbix:")
+		if err != nil {
+			return err
+		}
+		creds = zabbix.Credentials{APIToken: string(token)}
+	}
+
+	key := credentialStoreKey(cCtx)
+	if err := store.Set(key, creds); err != nil {
+		return err
+	}
+	outlog.Info("stored credentials", "url", key, "store", path)
+	return nil
+}
+
+func deleteCredentialAction(cCtx *cli.Context) error {
+	if _, err := requireURL(cCtx); err != nil {
+		return err
+	}
+	path := cCtx.String("credential-store")
+	if path == "" {
+		return errors.New(`"--credential-store" must be set`)
+	}
+
+	key := credentialStoreKey(cCtx)
+	if err := newCredentialStore(path).Delete(key); err != nil {
+		return err
+	}
+	outlog.Info("deleted credentials", "url", key, "store", path)
+	return nil
+}
+
+// readNewPassphrase prompts a passphrase for a new credential store twice.
+func readNewPassphrase() ([]byte, error) {
+	passphrase, err := readSecret("Enter new passphrase for credential store:")
+	if err != nil {
+		return nil, err
+	}
+	confirm, err := readSecret("Confirm passphrase:")
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(passphrase, confirm) {
+		return nil, errors.New("passphrases do not match")
+	}
+	return passphrase, nil
+}
@@ -2,78 +2,33 @@ package main
 
 import (
 	"context"
-	"time"
 
-	"github.com/hnakamur/go-zabbix/internal/rpc"
-	"github.com/hnakamur/go-zabbix/internal/slicex"
+	"github.com/hnakamur/go-zabbix/host"
 	"golang.org/x/exp/slices"
 )
 
-type Host struct {
-	HostID            string
-	Name              string
-	MaintenanceFrom   time.Time
-	MaintenanceStatus MaintenanceStatus
-	MaintenanceType   MaintenanceType
-	MaintenanceID     string
-}
+type Host = host.Host
 
-type MaintenanceStatus string
+type MaintenanceStatus = host.MaintenanceStatus
 
 const (
-	MaintenanceStatusNoMaintenance MaintenanceStatus = "0"
-	MaintenanceStatusInEffect      MaintenanceStatus = "1"
+	MaintenanceStatusNoMaintenance = host.MaintenanceStatusNoMaintenance
+	MaintenanceStatusInEffect      = host.MaintenanceStatusInEffect
 )
 
-func fromRPCHost(h rpc.Host) (Host, error) {
-	maintenanceFrom, err := ParseTimestamp(h.MaintenanceFrom)
-	if err != nil {
-		return Host{}, err
-	}
-
-	return Host{
-		HostID:            h.HostID,
-		Name:              h.Name,
-		MaintenanceFrom:   time.Time(maintenanceFrom),
-		MaintenanceStatus: MaintenanceStatus(h.MaintenanceStatus),
-		MaintenanceType:   MaintenanceType(h.MaintenanceType),
-		MaintenanceID:     h.MaintenanceID,
-	}, nil
-}
-
-func toRPCHost(h Host) (rpc.Host, error) {
-	return rpc.Host{
-		HostID: h.HostID,
-		Name:   h.Name,
-		// Keep empty values for readonly properties
-	}, nil
-}
-
 func (c *myClient) GetHostsByNamesFullMatch(ctx context.Context,
 	names []string) ([]Host, error) {
-	rh, err := c.inner.GetHostsByNamesFullMatch(ctx, names)
-	if err != nil {
-		return nil, err
-	}
-	return slicex.FailableMap(rh, fromRPCHost)
+	return host.GetByNamesFullMatch(ctx, c.inner, names)
 }
 
 func (c *myClient) GetHostsByGroupIDs(ctx context.Context,
 	groupIDs []string) ([]Host, error) {
-	rh, err := c.inner.GetHostsByGroupIDs(ctx, groupIDs)
-	if err != nil {
-		return nil, err
-	}
-	return slicex.FailableMap(rh, fromRPCHost)
+	return host.GetByGroupIDs(ctx, c.inner, groupIDs)
 }
 
 func (c *myClient) GetHostsByHostIDs(ctx context.Context,
 	hostIDs []string) ([]Host, error) {
-	rh, err := c.inner.GetHostsByHostIDs(ctx, hostIDs)
-	if err != nil {
-		return nil, err
-	}
-	return slicex.FailableMap(rh, fromRPCHost)
+	return host.GetByHostIDs(ctx, c.inner, hostIDs)
 }
 
 func sortHosts(hosts []Host) {
@@ -109,3 +64,12 @@ func (hh Hosts) allMaintenanceStatusExpected(expected MaintenanceStatus) bool {
 	}
 	return true
 }
+
+func (hh Hosts) allMaintenanceTypeExpected(expected MaintenanceType) bool {
+	for _, h := range hh {
+		if h.MaintenanceType != expected {
+			return false
+		}
+	}
+	return true
+}
@@ -3,17 +3,17 @@ package main
 import (
 	"context"
 
-	"github.com/hnakamur/go-zabbix/internal/rpc"
+	"github.com/hnakamur/go-zabbix/hostgroup"
 )
 
-type HostGroup = rpc.HostGroup
+type HostGroup = hostgroup.HostGroup
 
 func (c *myClient) GetHostGroupsByNamesFullMatch(ctx context.Context,
 	names []string) ([]HostGroup, error) {
-	return c.inner.GetHostGroupsByNamesFullMatch(ctx, names)
+	return hostgroup.GetByNamesFullMatch(ctx, c.inner, names)
 }
 
 func (c *myClient) GetNestedHostGroupsByAncestorNames(ctx context.Context,
 	names []string) ([]HostGroup, error) {
-	return c.inner.GetNestedHostGroupsByAncestorNames(ctx, names)
+	return hostgroup.GetNestedByAncestorNames(ctx, c.inner, names)
 }
@@ -1,5 +1,5 @@
 package main
 
-import "github.com/hnakamur/go-zabbix/internal/rpc"
+import "github.com/hnakamur/go-zabbix/item"
 
-type Item = rpc.Item
+type Item = item.Item
@@ -1,14 +1,18 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"log"
+	"log/slog"
 	"net/url"
 	"os"
+	"os/signal"
 	"runtime/debug"
 	"strings"
+	"syscall"
 	"time"
 
 	"golang.org/x/exp/slices"
@@ -16,8 +20,8 @@ import (
 	"github.com/hnakamur/go-zabbix"
 	"github.com/hnakamur/go-zabbix/internal/errlog"
 	"github.com/hnakamur/go-zabbix/internal/outlog"
-	"github.com/hnakamur/go-zabbix/internal/rpc"
 	"github.com/hnakamur/go-zabbix/internal/slicex"
+	"github.com/hnakamur/go-zabbix/trigger"
 	"github.com/urfave/cli/v2"
 )
 
@@ -25,29 +29,93 @@ const timeFormatRFC3339Minute = "2006-01-02T15:04"
 
 func main() {
 	if err := run(os.Args); err != nil {
-		errlog.Printf("ERROR %s", err)
+		errlog.Error("command failed", "err", err)
 		os.Exit(1)
 	}
 }
 
 func run(args []string) error {
-	app := &cli.App{
+	// Cancel the context on interrupt instead of exiting immediately, so
+	// that the session is logged out.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	return newApp().RunContext(ctx, args)
+}
+
+func newApp() *cli.App {
+	return &cli.App{
 		Name:    "zbx",
 		Usage:   "command line tool for Zabbix",
 		Version: Version(),
 		Flags: []cli.Flag{
 			&cli.StringFlag{
-				Name:     "url",
-				Aliases:  []string{"l"},
-				Usage:    "Zabbix URL (ex. http://example.com/zabbix)",
-				Required: true,
-				EnvVars:  []string{"ZBX_URL"},
+				Name:    "config",
+				Value:   defaultConfigPath(),
+				Usage:   "config file which has profiles of global flags",
+				EnvVars: []string{"ZBX_CONFIG"},
+			},
+			&cli.StringFlag{
+				Name:    "profile",
+				Aliases: []string{"P"},
+				Usage:   `profile in config file (default: "default-profile" in config file)`,
+				EnvVars: []string{"ZBX_PROFILE"},
+			},
+			&cli.StringFlag{
+				Name:    "url",
+				Aliases: []string{"l"},
+				Usage:   "Zabbix URL (ex. http://example.com/zabbix)",
+				EnvVars: []string{"ZBX_URL"},
 			},
 			&cli.StringFlag{
 				Name:    "virtual-host",
 				Usage:   "virtual host on Zabbix server",
 				EnvVars: []string{"ZBX_VIRTUAL_HOST"},
 			},
+			&cli.StringFlag{
+				Name:    "ca-cert",
+				Usage:   "PEM file of CA certificates to verify the Zabbix server certificate",
+				EnvVars: []string{"ZBX_CA_CERT"},
+			},
+			&cli.StringFlag{
+				Name:    "client-cert",
+				Usage:   "PEM file of client certificate for mutual TLS",
+				EnvVars: []string{"ZBX_CLIENT_CERT"},
+			},
+			&cli.StringFlag{
+				Name:    "client-key",
+				Usage:   "PEM file of client private key for mutual TLS",
+				EnvVars: []string{"ZBX_CLIENT_KEY"},
+			},
+			&cli.BoolFlag{
+				Name:    "insecure-skip-verify",
+				Usage:   "skip verifying the Zabbix server certificate (insecure)",
+				EnvVars: []string{"ZBX_INSECURE_SKIP_VERIFY"},
+			},
+			&cli.StringFlag{
+				Name:    "proxy",
+				Usage:   "HTTP proxy URL (default: proxy specified with HTTPS_PROXY or HTTP_PROXY)",
+				EnvVars: []string{"ZBX_PROXY"},
+			},
+			&cli.StringSliceFlag{
+				Name:    "header",
+				Usage:   `custom HTTP header in "Name: value" format (can be specified multiple times)`,
+				EnvVars: []string{"ZBX_HEADERS"},
+			},
+			&cli.StringFlag{
+				Name:    "basic-auth-user",
+				Usage:   "username for basic authentication of a reverse proxy in front of Zabbix (not supported with Zabbix 7.2 or later)",
+				EnvVars: []string{"ZBX_BASIC_AUTH_USER"},
+			},
+			&cli.StringFlag{
+				Name:    "basic-auth-password",
+				Usage:   "password for basic authentication of a reverse proxy in front of Zabbix",
+				EnvVars: []string{"ZBX_BASIC_AUTH_PASSWORD"},
+			},
+			&cli.DurationFlag{
+				Name:    "timeout",
+				Usage:   "timeout for each HTTP request (0 means no timeout)",
+				EnvVars: []string{"ZBX_TIMEOUT"},
+			},
 			&cli.StringFlag{
 				Name:    "username",
 				Aliases: []string{"u"},
@@ -57,14 +125,34 @@ func run(args []string) error {
 			&cli.StringFlag{
 				Name:    "password",
 				Aliases: []string{"p"},
-				Usage:   "login password (shows prompt if both of this and token are empty)",
+				Usage:   "login password (shows prompt if no other credentials are specified)",
 				EnvVars: []string{"ZBX_PASSWORD"},
 			},
+			&cli.StringFlag{
+				Name:    "password-command",
+				Usage:   `command to print login password (ex. "pass show zabbix/admin")`,
+				EnvVars: []string{"ZBX_PASSWORD_COMMAND"},
+			},
 			&cli.StringFlag{
 				Name:    "token",
 				Usage:   "Zabbix API token",
 				EnvVars: []string{"ZBX_API_TOKEN"},
 			},
+			&cli.StringFlag{
+				Name:    "token-file",
+				Usage:   "file containing Zabbix API token",
+				EnvVars: []string{"ZBX_API_TOKEN_FILE"},
+			},
+			&cli.StringFlag{
+				Name:    "session-cache",
+				Usage:   "file to keep session IDs to reuse in later invocations instead of logging out",
+				EnvVars: []string{"ZBX_SESSION_CACHE"},
+			},
+			&cli.StringFlag{
+				Name:    "credential-store",
+				Usage:   `encrypted file to store credentials with "zbx credential set"`,
+				EnvVars: []string{"ZBX_CREDENTIAL_STORE"},
+			},
 			&cli.BoolFlag{
 				Name:  "debug",
 				Usage: "print JSON-RPC requests and responses",
@@ -73,14 +161,41 @@ func run(args []string) error {
 				Name:  "dry-run",
 				Usage: "skip calling APIs to update, delete, or modify",
 			},
+			&cli.Float64Flag{
+				Name:    "rate-limit",
+				Usage:   "maximum number of requests per second to Zabbix (0 means unlimited)",
+				EnvVars: []string{"ZBX_RATE_LIMIT"},
+			},
 			&cli.GenericFlag{
 				Name:    "log-flags",
 				Value:   &logFlagsValue{flags: log.LstdFlags},
 				Usage:   "flags for logger (no prefix it set to empty)",
 				EnvVars: []string{"ZBX_LOG_FLAGS"},
 			},
+			&cli.GenericFlag{
+				Name:    "log-format",
+				Value:   &logFormatValue{format: outlog.FormatText},
+				Usage:   `log format ("text" or "json"; "--log-flags" is used only for "text")`,
+				EnvVars: []string{"ZBX_LOG_FORMAT"},
+			},
 		},
 		Commands: []*cli.Command{
+			{
+				Name:  "credential",
+				Usage: "set or delete credentials in the credential store",
+				Subcommands: []*cli.Command{
+					{
+						Name:   "set",
+						Usage:  `store the password for "--username", or the API token if "--username" is empty`,
+						Action: setCredentialAction,
+					},
+					{
+						Name:   "delete",
+						Usage:  "delete the credentials for the URL",
+						Action: deleteCredentialAction,
+					},
+				},
+			},
 			{
 				Name:  "mainte",
 				Usage: "create, update, or delete maintenance",
@@ -88,7 +203,7 @@ func run(args []string) error {
 					{
 						Name:  "create",
 						Usage: "create a maintenance",
-						Flags: []cli.Flag{
+						Flags: append([]cli.Flag{
 							&cli.StringFlag{
 								Name:     "name",
 								Aliases:  []string{"n"},
@@ -118,19 +233,19 @@ func run(args []string) error {
 								Name:     "active-since",
 								Layout:   timeFormatRFC3339Minute,
 								Timezone: time.Local,
-								Usage:    `active start time of maintenance (default: same as "--start-date")`,
+								Usage:    `active start time of maintenance (default: same as "--start-date" for one-time, now for recurring)`,
 							},
 							&cli.TimestampFlag{
 								Name:     "active-till",
 								Layout:   timeFormatRFC3339Minute,
 								Timezone: time.Local,
-								Usage:    `active end time of maintenance (default: "--start-date" + "--period")`,
+								Usage:    `active end time of maintenance (default: "--start-date" + "--period" for one-time, required for recurring)`,
 							},
 							&cli.TimestampFlag{
 								Name:     "start-date",
 								Layout:   timeFormatRFC3339Minute,
 								Timezone: time.Local,
-								Usage:    "start time of maintenance (default: now)",
+								Usage:    "start time of one-time maintenance (default: now)",
 							},
 							&cli.DurationFlag{
 								Name:     "period",
@@ -148,7 +263,21 @@ func run(args []string) error {
 								Value: 30 * time.Second,
 								Usage: "polling interval",
 							},
-						},
+							&cli.GenericFlag{
+								Name:  "type",
+								Value: &maintenanceTypeValue{typ: MaintenanceTypeWithData},
+								Usage: "maintenance type: with-data or no-data (data collection)",
+							},
+							&cli.StringSliceFlag{
+								Name:  "tag",
+								Usage: "problem tags in key=value (equals) or key~value (contains)",
+							},
+							&cli.GenericFlag{
+								Name:  "tags-evaltype",
+								Value: &tagsEvalTypeValue{evalType: TagsEvalTypeAndOr},
+								Usage: "evaluation type of problem tags: and-or or or",
+							},
+						}, timePeriodFlags()...),
 						Action: createMaintenanceAction,
 					},
 					{
@@ -159,7 +288,7 @@ func run(args []string) error {
 					{
 						Name:  "update",
 						Usage: "update a maintenance",
-						Flags: []cli.Flag{
+						Flags: append([]cli.Flag{
 							&cli.StringFlag{
 								Name:    "id",
 								Aliases: []string{"i"},
@@ -209,13 +338,29 @@ func run(args []string) error {
 								Name:     "start-date",
 								Layout:   "2006-01-02T15:04",
 								Timezone: time.Local,
-								Usage:    "start time of maintenance",
+								Usage:    "start time of one-time maintenance",
 							},
 							&cli.DurationFlag{
 								Name:    "period",
 								Aliases: []string{"p"},
 								Usage:   "duration of maintenance",
 							},
+							&cli.StringFlag{
+								Name:  "timeperiod-id",
+								Usage: "ID of the time period to update or remove",
+							},
+							&cli.IntFlag{
+								Name:  "timeperiod-index",
+								Usage: `index of the time period to update or remove (1 for the first one in "mainte get")`,
+							},
+							&cli.BoolFlag{
+								Name:  "add-timeperiod",
+								Usage: "add a new time period instead of updating an existing one",
+							},
+							&cli.BoolFlag{
+								Name:  "remove-timeperiod",
+								Usage: `remove the time period selected with "--timeperiod-id" or "--timeperiod-index"`,
+							},
 							&cli.BoolFlag{
 								Name:    "wait",
 								Aliases: []string{"w"},
@@ -226,9 +371,40 @@ func run(args []string) error {
 								Value: 30 * time.Second,
 								Usage: "polling interval",
 							},
-						},
+							&cli.GenericFlag{
+								Name:  "type",
+								Value: &maintenanceTypeValue{},
+								Usage: "maintenance type: with-data or no-data (data collection)",
+							},
+							&cli.StringSliceFlag{
+								Name:  "tag",
+								Usage: "problem tags in key=value (equals) or key~value (contains) (or set empty string just once to clear tags)",
+							},
+							&cli.GenericFlag{
+								Name:  "tags-evaltype",
+								Value: &tagsEvalTypeValue{},
+								Usage: "evaluation type of problem tags: and-or or or",
+							},
+						}, timePeriodFlags()...),
 						Action: updateMaintenanceAction,
 					},
+					{
+						Name:  "apply",
+						Usage: "create, update, or delete maintenances to match a file",
+						Flags: []cli.Flag{
+							&cli.StringFlag{
+								Name:     "file",
+								Aliases:  []string{"f"},
+								Required: true,
+								Usage:    `YAML or JSON file of maintenances ("-" for stdin)`,
+							},
+							&cli.BoolFlag{
+								Name:  "prune",
+								Usage: `delete maintenances with "managed-by" of the file which are not in the file`,
+							},
+						},
+						Action: applyMaintenancesAction,
+					},
 					{
 						Name:  "delete",
 						Usage: "delete maintenance(s)",
@@ -364,16 +540,20 @@ func run(args []string) error {
 			},
 		},
 		Before: func(cCtx *cli.Context) error {
+			if err := applyProfile(cCtx); err != nil {
+				return err
+			}
 			logFlags := cCtx.Generic("log-flags").(*logFlagsValue).flags
-			outlog.SetFlags(logFlags)
-			outlog.SetOutput(cCtx.App.Writer)
-			errlog.SetFlags(logFlags)
-			errlog.SetOutput(cCtx.App.ErrWriter)
+			logFormat := cCtx.Generic("log-format").(*logFormatValue).format
+			level := slog.LevelInfo
+			if cCtx.Bool("debug") {
+				level = slog.LevelDebug
+			}
+			outlog.SetLogger(slog.New(outlog.NewHandler(cCtx.App.Writer, logFormat, logFlags, level)))
+			errlog.SetLogger(slog.New(outlog.NewHandler(cCtx.App.ErrWriter, logFormat, logFlags, level)))
 			return nil
 		},
 	}
-
-	return app.Run(args)
 }
 
 type logFlagsValue struct {
@@ -393,6 +573,23 @@ func (v *logFlagsValue) String() string {
 	return outlog.LogFlags(v.flags).String()
 }
 
+type logFormatValue struct {
+	format outlog.Format
+}
+
+func (v *logFormatValue) Set(value string) error {
+	format, err := outlog.ParseFormat(value)
+	if err != nil {
+		return err
+	}
+	v.format = format
+	return nil
+}
+
+func (v *logFormatValue) String() string {
+	return string(v.format)
+}
+
 func createMaintenanceAction(cCtx *cli.Context) error {
 	hostNames := cCtx.StringSlice("host")
 	groupNames := cCtx.StringSlice("group")
@@ -400,99 +597,75 @@ func createMaintenanceAction(cCtx *cli.Context) error {
 		return errors.New(`at least "--host" or "--hostgroup" must be set`)
 	}
 
-	client, err := newClient(cCtx)
+	timePeriod, err := newTimePeriod(cCtx)
 	if err != nil {
 		return err
 	}
 
-	hostsJustID := []Host{}
-	if len(hostNames) > 0 {
-		hosts, err := client.GetHostsByNamesFullMatch(cCtx.Context, hostNames)
-		if err != nil {
-			return err
+	activeSince := cCtx.Timestamp("active-since")
+	activeTill := cCtx.Timestamp("active-till")
+	if timePeriod.TimeperiodType == TimeperiodTypeOnetimeOnly {
+		if activeSince == nil {
+			activeSince = &timePeriod.StartDate
 		}
-		hostsJustID = slicex.Map(hosts, func(h Host) Host {
-			return Host{HostID: h.HostID}
-		})
-	}
-
-	groupsJustID := []HostGroup{}
-	if len(groupNames) > 0 {
-		var groups []HostGroup
-		if cCtx.Bool("include-nested") {
-			groups, err = client.GetNestedHostGroupsByAncestorNames(cCtx.Context, groupNames)
-			if err != nil {
-				return err
-			}
-			if cCtx.Bool("debug") {
-				groupNames := slicex.Map(groups, func(g HostGroup) string {
-					return g.Name
-				})
-				log.Printf("DEBUG expaneded groups=%s", groupNames)
-			}
-		} else {
-			groups, err = client.GetHostGroupsByNamesFullMatch(cCtx.Context, groupNames)
-			if err != nil {
-				return err
-			}
+		if activeTill == nil {
+			endDate := timePeriod.StartDate.Add(timePeriod.Period)
+			activeTill = &endDate
+		}
+	} else {
+		if activeTill == nil {
+			return errors.New(`"--active-till" must be set for recurring time periods`)
+		}
+		if activeSince == nil {
+			now := time.Now().Truncate(time.Minute)
+			activeSince = &now
 		}
-		groupsJustID = slicex.Map(groups, func(g HostGroup) HostGroup {
-			return HostGroup{GroupID: g.GroupID}
-		})
 	}
 
-	period := cCtx.Duration("period")
-	startDate := cCtx.Timestamp("start-date")
-	if startDate == nil {
-		now := time.Now().Truncate(time.Minute)
-		startDate = &now
+	maintenance := &Maintenance{
+		Name:            cCtx.String("name"),
+		ActiveSince:     *activeSince,
+		ActiveTill:      *activeTill,
+		Description:     cCtx.String("desc"),
+		MaintenanceType: cCtx.Generic("type").(*maintenanceTypeValue).typ,
+		TagsEvalType:    TagsEvalTypeAndOr,
+		TimePeriods:     []TimePeriod{timePeriod},
+	}
+	if err := applyTagFlags(cCtx, maintenance); err != nil {
+		return err
 	}
 
-	activeSince := cCtx.Timestamp("active-since")
-	if activeSince == nil {
-		activeSince = startDate
+	client, err := newClient(cCtx)
+	if err != nil {
+		return err
 	}
+	defer client.close(cCtx.Context)
 
-	activeTill := cCtx.Timestamp("active-till")
-	if activeTill == nil {
-		endDate := startDate.Add(period)
-		activeTill = &endDate
+	maintenance.Hosts, err = getHostsJustID(cCtx, client, hostNames)
+	if err != nil {
+		return err
 	}
-
-	maintenance := &Maintenance{
-		Name:           cCtx.String("name"),
-		ActiveSince:    *activeSince,
-		ActiveTill:     *activeTill,
-		Description:    cCtx.String("desc"),
-		MaintenaceType: MaintenanceTypeWithData,
-		TagsEvalType:   TagsEvalTypeAndOr,
-		Groups:         groupsJustID,
-		Hosts:          hostsJustID,
-		TimePeriods: []TimePeriod{
-			{
-				Period:         period,
-				TimeperiodType: TimeperiodTypeOnetimeOnly,
-				StartDate:      *startDate,
-			},
-		},
+	maintenance.Groups, err = getHostGroupsJustID(cCtx, client, groupNames, cCtx.Bool("include-nested"))
+	if err != nil {
+		return err
 	}
 
 	if cCtx.Bool("dry-run") {
-		outlog.Printf("INFO skip creating maintenance due to dry run, name: %s", cCtx.String("name"))
+		outlog.Info("skip creating maintenance due to dry run", "name", cCtx.String("name"))
 		return nil
 	}
 	if err := client.CreateMaintenance(cCtx.Context, maintenance); err != nil {
 		return err
 	}
 
-	u, err := maintenanceURL(cCtx, maintenance.MaintenaceID)
+	u, err := maintenanceURL(cCtx, maintenance.MaintenanceID)
 	if err != nil {
 		return err
 	}
-	outlog.Printf("INFO created maintenance, url: %s", u.String())
+	outlog.Info("created maintenance", "maintenance_id", maintenance.MaintenanceID, "url", u.String())
 
 	if cCtx.Bool("wait") {
-		if err := waitForMaintenanceInEffect(cCtx, client, maintenance.MaintenaceID); err != nil {
+		if err := waitForMaintenanceInEffect(cCtx, client, maintenance.MaintenanceID); err != nil {
 			return err
 		}
 	}
@@ -505,25 +678,20 @@ func updateMaintenanceAction(cCtx *cli.Context) error {
 	if err != nil {
 		return err
 	}
+	defer client.close(cCtx.Context)
 	maintenance, err := getTargetMaintenance(cCtx, client)
 	if err != nil {
 		return err
 	}
-	if len(maintenance.TimePeriods) != 1 {
-		return fmt.Errorf("unsupported TimePeriod count: got=%d, want=1", len(maintenance.TimePeriods))
-	}
 
 	if hostNames := cCtx.StringSlice("host"); len(hostNames) > 0 {
 		if len(hostNames) == 1 && hostNames[0] == "" {
 			maintenance.Hosts = []Host{}
 		} else {
-			hosts, err := client.GetHostsByNamesFullMatch(cCtx.Context, hostNames)
+			maintenance.Hosts, err = getHostsJustID(cCtx, client, hostNames)
 			if err != nil {
 				return err
 			}
-			maintenance.Hosts = slicex.Map(hosts, func(h Host) Host {
-				return Host{HostID: h.HostID}
-			})
 		}
 	} else {
 		maintenance.Hosts = slicex.Map(maintenance.Hosts, func(h Host) Host {
@@ -535,27 +703,10 @@ func updateMaintenanceAction(cCtx *cli.Context) error {
 		if len(groupNames) == 1 && groupNames[0] == "" {
 			maintenance.Groups = []HostGroup{}
 		} else {
-			var groups []HostGroup
-			if cCtx.Bool("include-nested") {
-				groups, err = client.GetNestedHostGroupsByAncestorNames(cCtx.Context, groupNames)
-				if err != nil {
-					return err
-				}
-				if cCtx.Bool("debug") {
-					groupNames := slicex.Map(groups, func(g HostGroup) string {
-						return g.Name
-					})
-					log.Printf("DEBUG expaneded groups=%s", groupNames)
-				}
-			} else {
-				groups, err = client.GetHostGroupsByNamesFullMatch(cCtx.Context, groupNames)
-				if err != nil {
-					return err
-				}
+			maintenance.Groups, err = getHostGroupsJustID(cCtx, client, groupNames, cCtx.Bool("include-nested"))
+			if err != nil {
+				return err
 			}
-			maintenance.Groups = slicex.Map(groups, func(g HostGroup) HostGroup {
-				return HostGroup{GroupID: g.GroupID}
-			})
 		}
 	} else {
 		maintenance.Groups = slicex.Map(maintenance.Groups, func(g HostGroup) HostGroup {
@@ -575,32 +726,32 @@ func updateMaintenanceAction(cCtx *cli.Context) error {
 	if t := cCtx.Timestamp("active-till"); t != nil {
 		maintenance.ActiveTill = *t
 	}
-	if t := cCtx.Timestamp("start-date"); t != nil {
-		maintenance.TimePeriods[0].StartDate = *t
+	if err := updateTimePeriods(cCtx, maintenance); err != nil {
+		return err
 	}
-	if t := cCtx.Timestamp("start-date"); t != nil {
-		maintenance.TimePeriods[0].StartDate = *t
+	if cCtx.IsSet("type") {
+		maintenance.MaintenanceType = cCtx.Generic("type").(*maintenanceTypeValue).typ
 	}
-	if d := cCtx.Duration("period"); d != 0 {
-		maintenance.TimePeriods[0].Period = d
+	if err := applyTagFlags(cCtx, maintenance); err != nil {
+		return err
 	}
 
 	if cCtx.Bool("dry-run") {
-		outlog.Printf("INFO skip updating maintenance due to dry run, name: %s, id: %s", maintenance.Name, maintenance.MaintenaceID)
+		outlog.Info("skip updating maintenance due to dry run", "name", maintenance.Name, "maintenance_id", maintenance.MaintenanceID)
 		return nil
 	}
 	if err := client.UpdateMaintenance(cCtx.Context, maintenance); err != nil {
 		return err
 	}
 
-	u, err := maintenanceURL(cCtx, maintenance.MaintenaceID)
+	u, err := maintenanceURL(cCtx, maintenance.MaintenanceID)
 	if err != nil {
 		return err
 	}
-	outlog.Printf("INFO updated maintenance, url: %s", u.String())
+	outlog.Info("updated maintenance", "maintenance_id", maintenance.MaintenanceID, "url", u.String())
 
 	if cCtx.Bool("wait") {
-		if err := waitForMaintenanceInEffect(cCtx, client, maintenance.MaintenaceID); err != nil {
+		if err := waitForMaintenanceInEffect(cCtx, client, maintenance.MaintenanceID); err != nil {
 			return err
 		}
 	}
@@ -608,28 +759,70 @@ func updateMaintenanceAction(cCtx *cli.Context) error {
 	return nil
 }
 
+// getHostsJustID returns the hosts of names with only IDs set, which are
+// used to set hosts of a maintenance.
+func getHostsJustID(cCtx *cli.Context, client *myClient, names []string) ([]Host, error) {
+	if len(names) == 0 {
+		return []Host{}, nil
+	}
+	hosts, err := client.GetHostsByNamesFullMatch(cCtx.Context, names)
+	if err != nil {
+		return nil, err
+	}
+	return slicex.Map(hosts, func(h Host) Host {
+		return Host{HostID: h.HostID}
+	}), nil
+}
+
+// getHostGroupsJustID returns the host groups of names, and their nested
+// host groups if includeNested is true, with only IDs set.
+func getHostGroupsJustID(cCtx *cli.Context, client *myClient, names []string, includeNested bool) ([]HostGroup, error) {
+	if len(names) == 0 {
+		return []HostGroup{}, nil
+	}
+	var groups []HostGroup
+	var err error
+	if includeNested {
+		groups, err = client.GetNestedHostGroupsByAncestorNames(cCtx.Context, names)
+		if err != nil {
+			return nil, err
+		}
+		if cCtx.Bool("debug") {
+			groupNames := slicex.Map(groups, func(g HostGroup) string {
+				return g.Name
+			})
+			errlog.Debug("expanded groups", "groups", groupNames)
+		}
+	} else {
+		groups, err = client.GetHostGroupsByNamesFullMatch(cCtx.Context, names)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return slicex.Map(groups, func(g HostGroup) HostGroup {
+		return HostGroup{GroupID: g.GroupID}
+	}), nil
+}
+
 func getMaintenancesAction(cCtx *cli.Context) error {
 	client, err := newClient(cCtx)
 	if err != nil {
 		return err
 	}
+	defer client.close(cCtx.Context)
 
 	maintenances, err := client.GetMaintenances(cCtx.Context)
 	if err != nil {
 		return err
 	}
 	slices.SortFunc(maintenances, func(a, b Maintenance) bool {
-		return a.MaintenaceID < b.MaintenaceID
+		return a.MaintenanceID < b.MaintenanceID
 	})
 
-	outlog.Printf("INFO maintenance count: %d", len(maintenances))
+	outlog.Info("maintenance count", "count", len(maintenances))
 	for i, m := range maintenances {
-		dm := toDisplayMaintenance(m)
-		resultBytes, err := json.Marshal(dm)
-		if err != nil {
-			return err
-		}
-		outlog.Printf("INFO maintenance i=%d, %s", i, string(resultBytes))
+		outlog.Info("maintenance", "i", i, "maintenance_id", m.MaintenanceID,
+			"maintenance", toDisplayMaintenance(m))
 	}
 	return nil
 }
@@ -651,6 +844,7 @@ func deleteMaintenanceAction(cCtx *cli.Context) error {
 	if err != nil {
 		return err
 	}
+	defer client.close(cCtx.Context)
 
 	var idsByIDs, idsByNames []string
 	if len(ids) > 0 {
@@ -668,24 +862,14 @@ func deleteMaintenanceAction(cCtx *cli.Context) error {
 	targetIDs := slicex.ConcatDeDup(idsByIDs, idsByNames)
 
 	if cCtx.Bool("dry-run") {
-		var b strings.Builder
-		if len(ids) > 0 {
-			fmt.Fprintf(&b, "ids: %s", strings.Join(ids, ", "))
-		}
-		if len(names) > 0 {
-			if b.Len() > 0 {
-				b.WriteString(", ")
-			}
-			fmt.Fprintf(&b, "names: %s", strings.Join(names, ", "))
-		}
-		outlog.Printf("INFO skip deleting maintenance due to dry run, %s", b.String())
+		outlog.Info("skip deleting maintenance due to dry run", "ids", ids, "names", names)
 		return nil
 	}
 	deletedIDs, err := client.DeleteMaintenancesByIDs(cCtx.Context, targetIDs)
 	if err != nil {
 		return err
 	}
-	outlog.Printf("INFO targetIDs=%v, deletedIDs=%v", targetIDs, deletedIDs)
+	outlog.Info("deleted maintenances", "target_ids", targetIDs, "deleted_ids", deletedIDs)
 	return nil
 }
 
@@ -694,6 +878,7 @@ func showStatusAction(cCtx *cli.Context) error {
 	if err != nil {
 		return err
 	}
+	defer client.close(cCtx.Context)
 	maintenance, err := getTargetMaintenance(cCtx, client)
 	if err != nil {
 		return err
@@ -704,18 +889,12 @@ func showStatusAction(cCtx *cli.Context) error {
 		return err
 	}
 
-	if mainteBytes, err := json.Marshal(toDisplayMaintenance(*maintenance)); err != nil {
-		return err
-	} else {
-		outlog.Printf("INFO maintenance=%s", string(mainteBytes))
-	}
-
-	if err := logHosts(hosts); err != nil {
-		return err
-	}
+	outlog.Info("maintenance", "maintenance_id", maintenance.MaintenanceID,
+		"maintenance", toDisplayMaintenance(*maintenance))
+	logHosts(maintenance.MaintenanceID, hosts)
 
 	if cCtx.Bool("wait") {
-		if err := waitForMaintenanceInEffect(cCtx, client, maintenance.MaintenaceID); err != nil {
+		if err := waitForMaintenanceInEffect(cCtx, client, maintenance.MaintenanceID); err != nil {
 			return err
 		}
 	}
@@ -736,11 +915,11 @@ func waitForMaintenanceInEffect(cCtx *cli.Context, client *myClient, maintenance
 			return err
 		}
 
-		if Hosts(hosts).allMaintenanceStatusExpected(MaintenanceStatusInEffect) {
-			outlog.Printf("INFO all hosts in specified maintenance become in effect status")
-			if err := logHosts(hosts); err != nil {
-				return err
-			}
+		if Hosts(hosts).allMaintenanceStatusExpected(MaintenanceStatusInEffect) &&
+			Hosts(hosts).allMaintenanceTypeExpected(maintenance.MaintenanceType) {
+			outlog.Info("all hosts in specified maintenance become in effect status",
+				"maintenance_id", maintenanceID)
+			logHosts(maintenanceID, hosts)
 			return nil
 		}
 
@@ -750,10 +929,11 @@ func waitForMaintenanceInEffect(cCtx *cli.Context, client *myClient, maintenance
 		} else {
 			timer.Reset(interval)
 		}
-		outlog.Print("waiting for maintenance statuses change in all hosts...")
+		outlog.Info("waiting for maintenance statuses change in all hosts...",
+			"maintenance_id", maintenanceID)
 		select {
 		case <-cCtx.Context.Done():
-			return nil
+			return cCtx.Context.Err()
 		case <-timer.C:
 		}
 	}
@@ -777,45 +957,137 @@ func getHostsInMaintenance(cCtx *cli.Context, client *myClient, maintenance *Mai
 	return hosts, nil
 }
 
-func logHosts(hosts []Host) error {
-	hostsBytes, err := json.Marshal(slicex.Map(hosts, toDisplayHost))
-	if err != nil {
-		return err
-	}
-	outlog.Printf("INFO hosts=%s", string(hostsBytes))
-	return nil
+func logHosts(maintenanceID string, hosts []Host) {
+	hostNames := slicex.Map(hosts, func(h Host) string {
+		return h.Name
+	})
+	outlog.Info("hosts", "maintenance_id", maintenanceID, "host_names", hostNames,
+		"hosts", slicex.Map(hosts, toDisplayHost))
 }
 
 func newClient(cCtx *cli.Context) (*myClient, error) {
-	zabbixURL := cCtx.String("url")
+	zabbixURL, err := requireURL(cCtx)
+	if err != nil {
+		return nil, err
+	}
 	hostHeader := cCtx.String("virtual-host")
 
 	var opts []zabbix.ClientOpt
 	if hostHeader != "" {
 		opts = append(opts, zabbix.WithHost(hostHeader))
 	}
+	httpOpts, err := httpClientOpts(cCtx)
+	if err != nil {
+		return nil, err
+	}
+	opts = append(opts, httpOpts...)
 
-	token := cCtx.String("token")
-	if token != "" {
-		opts = append(opts, zabbix.WithAPIToken(token))
+	provider, err := credentialsProvider(cCtx)
+	if err != nil {
+		return nil, err
+	}
+	// Authenticate again when the session or the token expires while waiting.
+	opts = append(opts, zabbix.WithCredentialsProvider(provider))
+	if rateLimit := cCtx.Float64("rate-limit"); rateLimit > 0 {
+		opts = append(opts, zabbix.WithRateLimit(rateLimit, 1))
+	}
+	opts = append(opts, zabbix.WithDebug(cCtx.Bool("debug")),
+		zabbix.WithLogger(errlog.Logger()))
+
+	var cache *sessionCache
+	var cached *sessionCacheEntry
+	// Sessions are cached only for logging in with a username and a password.
+	if path := cCtx.String("session-cache"); path != "" &&
+		cCtx.String("token") == "" && cCtx.String("token-file") == "" {
+		cache = newSessionCache(cCtx, path)
+		if cached, err = cache.get(); err != nil {
+			return nil, err
+		}
+		if cached != nil {
+			if apiVer, err := zabbix.ParseAPIVersion(cached.APIVersion); err == nil {
+				opts = append(opts, zabbix.WithAPIVersion(apiVer))
+			}
+		}
 	}
-	opts = append(opts, zabbix.WithDebug(cCtx.Bool("debug")))
 
 	c, err := zabbix.NewClient(zabbixURL, opts...)
 	if err != nil {
 		return nil, err
 	}
 
-	client := &myClient{inner: &rpc.Client{Client: c}}
-	if token == "" {
-		if err := login(cCtx, client); err != nil {
+	client := &myClient{inner: c, sessionCache: cache}
+	if cached != nil {
+		ok, err := client.resumeSession(cCtx.Context, cached.SessionID)
+		if err != nil {
 			return nil, err
 		}
+		if ok {
+			return client, nil
+		}
 	}
 
+	creds, err := provider.Credentials(cCtx.Context)
+	if err != nil {
+		return nil, err
+	}
+	if creds.APIToken != "" {
+		c.SetAPIToken(creds.APIToken)
+		return client, nil
+	}
+	if err := c.Login(cCtx.Context, creds.Username, creds.Password); err != nil {
+		return nil, err
+	}
+	client.loggedIn = true
 	return client, nil
 }
 
+// requireURL returns the Zabbix URL, which is required for commands calling
+// APIs. It is checked here instead of making the flag required, since it can
+// be set in a profile.
+func requireURL(cCtx *cli.Context) (string, error) {
+	u := cCtx.String("url")
+	if u == "" {
+		return "", errors.New(`"--url" must be set in flags, environment variables, or a profile`)
+	}
+	return u, nil
+}
+
+// httpClientOpts returns the options for TLS, proxy, headers and timeout.
+func httpClientOpts(cCtx *cli.Context) ([]zabbix.ClientOpt, error) {
+	var opts []zabbix.ClientOpt
+	if caCert := cCtx.String("ca-cert"); caCert != "" {
+		opts = append(opts, zabbix.WithCACertFile(caCert))
+	}
+	clientCert := cCtx.String("client-cert")
+	clientKey := cCtx.String("client-key")
+	if clientCert != "" || clientKey != "" {
+		if clientCert == "" || clientKey == "" {
+			return nil, errors.New(`both of "--client-cert" and "--client-key" must be set`)
+		}
+		opts = append(opts, zabbix.WithClientCertFile(clientCert, clientKey))
+	}
+	if cCtx.Bool("insecure-skip-verify") {
+		opts = append(opts, zabbix.WithInsecureSkipVerify(true))
+	}
+	if proxy := cCtx.String("proxy"); proxy != "" {
+		opts = append(opts, zabbix.WithProxy(proxy))
+	}
+	for _, header := range cCtx.StringSlice("header") {
+		name, value, ok := strings.Cut(header, ":")
+		if !ok || strings.TrimSpace(name) == "" {
+			return nil, fmt.Errorf(`invalid header %q, must be in "Name: value" format`, header)
+		}
+		opts = append(opts, zabbix.WithHeader(strings.TrimSpace(name), strings.TrimSpace(value)))
+	}
+	if user := cCtx.String("basic-auth-user"); user != "" {
+		opts = append(opts, zabbix.WithBasicAuth(user, cCtx.String("basic-auth-password")))
+	}
+	if timeout := cCtx.Duration("timeout"); timeout > 0 {
+		opts = append(opts, zabbix.WithTimeout(timeout))
+	}
+	return opts, nil
+}
+
 func getTargetMaintenance(cCtx *cli.Context, client *myClient) (*Maintenance, error) {
 	id := cCtx.String("id")
 	name := cCtx.String("name")
@@ -850,6 +1122,7 @@ func disableTriggersAction(cCtx *cli.Context) error {
 	if err != nil {
 		return err
 	}
+	defer client.close(cCtx.Context)
 
 	triggerIDs, err = client.GetTriggerIDs(cCtx.Context, triggerIDs, hostNames, groupNames, descriptions)
 	if err != nil {
@@ -860,7 +1133,7 @@ func disableTriggersAction(cCtx *cli.Context) error {
 		return errors.New("no trigger matched")
 	}
 
-	ids, err := client.SetTriggersStatus(cCtx.Context, triggerIDs, rpc.TriggerStatusDisabled)
+	ids, err := client.SetTriggersStatus(cCtx.Context, triggerIDs, trigger.StatusDisabled)
 	// Print updated trigger IDs before returning the error
 	// since some triggers may be updated.
 	enc := json.NewEncoder(os.Stdout)
@@ -888,6 +1161,7 @@ func enableTriggersAction(cCtx *cli.Context) error {
 	if err != nil {
 		return err
 	}
+	defer client.close(cCtx.Context)
 
 	triggerIDs, err = client.GetTriggerIDs(cCtx.Context, triggerIDs, hostNames, groupNames, descriptions)
 	if err != nil {
@@ -898,7 +1172,7 @@ func enableTriggersAction(cCtx *cli.Context) error {
 		return errors.New("no trigger matched")
 	}
 
-	ids, err := client.SetTriggersStatus(cCtx.Context, triggerIDs, rpc.TriggerStatusEnabled)
+	ids, err := client.SetTriggersStatus(cCtx.Context, triggerIDs, trigger.StatusEnabled)
 	// Print updated trigger IDs before returning the error
 	// since some triggers may be updated.
 	enc := json.NewEncoder(os.Stdout)
@@ -926,6 +1200,7 @@ func getTriggersAction(cCtx *cli.Context) error {
 	if err != nil {
 		return err
 	}
+	defer client.close(cCtx.Context)
 
 	triggers, err := client.GetTriggers(cCtx.Context, triggerIDs, hostNames, groupNames, descriptions)
 	if err != nil {
@@ -946,28 +1221,6 @@ func getTriggersAction(cCtx *cli.Context) error {
 	return nil
 }
 
-func login(cCtx *cli.Context, c *myClient) error {
-	username := cCtx.String("username")
-	password := cCtx.String("password")
-
-	if username == "" {
-		return errors.New(`"--token" or "--username" must be set`)
-	}
-
-	if password == "" {
-		p, err := readSecret("Enter password for Zabbix:")
-		if err != nil {
-			return err
-		}
-		password = string(p)
-	}
-
-	if err := c.inner.Login(cCtx.Context, username, password); err != nil {
-		return err
-	}
-	return nil
-}
-
 func maintenanceURL(cCtx *cli.Context, maintenanceID string) (*url.URL, error) {
 	zabbixURL, err := url.Parse(cCtx.String("url"))
 	if err != nil {
@@ -2,213 +2,124 @@ package main
 
 import (
 	"context"
+	"fmt"
 	"strings"
 	"time"
 
-	"github.com/hnakamur/go-zabbix/internal/rpc"
+	"github.com/hnakamur/go-zabbix/host"
 	"github.com/hnakamur/go-zabbix/internal/slicex"
+	"github.com/hnakamur/go-zabbix/maintenance"
 )
 
-// https://www.zabbix.com/documentation/6.0/en/manual/api/reference/maintenance/object
-type Maintenance struct {
-	MaintenaceID   string
-	Name           string
-	ActiveSince    time.Time
-	ActiveTill     time.Time
-	Description    string
-	MaintenaceType MaintenanceType
-	TagsEvalType   TagsEvalType
-	Groups         []HostGroup
-	Hosts          []Host
-	TimePeriods    []TimePeriod
-}
+type Maintenance = maintenance.Maintenance
 
-type MaintenanceType string
+type MaintenanceType = host.MaintenanceType
 
 const (
-	MaintenanceTypeWithData MaintenanceType = "0"
-	MaintenanceTypeNoData   MaintenanceType = "1"
+	MaintenanceTypeWithData = host.MaintenanceTypeWithData
+	MaintenanceTypeNoData   = host.MaintenanceTypeNoData
 )
 
-type TagsEvalType string
+var maintenanceTypeNames = []struct {
+	typ  MaintenanceType
+	name string
+}{
+	{MaintenanceTypeWithData, "with-data"},
+	{MaintenanceTypeNoData, "no-data"},
+}
 
-const (
-	TagsEvalTypeAndOr TagsEvalType = "0"
-	TagsEvalTypeOr    TagsEvalType = "1"
-)
+type maintenanceTypeValue struct {
+	typ MaintenanceType
+}
 
-func fromPRCMaintenance(m rpc.Maintenance) (Maintenance, error) {
-	activeSince, err := ParseTimestamp(m.ActiveSince)
-	if err != nil {
-		return Maintenance{}, err
-	}
-	activeTill, err := ParseTimestamp(m.ActiveTill)
-	if err != nil {
-		return Maintenance{}, err
-	}
-	hosts, err := slicex.FailableMap(m.Hosts, fromRPCHost)
-	if err != nil {
-		return Maintenance{}, err
-	}
-	timePeriods, err := slicex.FailableMap(m.TimePeriods, fromRPCTimePeriod)
-	if err != nil {
-		return Maintenance{}, err
+func (v *maintenanceTypeValue) Set(value string) error {
+	for _, n := range maintenanceTypeNames {
+		if n.name == value {
+			v.typ = n.typ
+			return nil
+		}
 	}
-
-	return Maintenance{
-		MaintenaceID:   m.MaintenaceID,
-		Name:           m.Name,
-		ActiveSince:    time.Time(activeSince),
-		ActiveTill:     time.Time(activeTill),
-		Description:    m.Description,
-		MaintenaceType: MaintenanceType(m.MaintenaceType),
-		TagsEvalType:   TagsEvalType(m.TagsEvalType),
-		Groups:         m.Groups,
-		Hosts:          hosts,
-		TimePeriods:    timePeriods,
-	}, nil
+	return fmt.Errorf("invalid maintenance type: %q", value)
 }
 
-func toRPCMaintenance(m Maintenance) (rpc.Maintenance, error) {
-	rpcHosts, err := slicex.FailableMap(m.Hosts, toRPCHost)
-	if err != nil {
-		return rpc.Maintenance{}, err
-	}
-	rpcTimePeriods, err := slicex.FailableMap(m.TimePeriods, toRPCTimePeriod)
-	if err != nil {
-		return rpc.Maintenance{}, err
+func (v *maintenanceTypeValue) String() string {
+	if v.typ == "" {
+		return ""
 	}
+	return maintenanceTypeName(v.typ)
+}
 
-	return rpc.Maintenance{
-		MaintenaceID:   m.MaintenaceID,
-		Name:           m.Name,
-		ActiveSince:    Timestamp(m.ActiveSince).String(),
-		ActiveTill:     Timestamp(m.ActiveTill).String(),
-		Description:    m.Description,
-		MaintenaceType: string(m.MaintenaceType),
-		TagsEvalType:   string(m.TagsEvalType),
-		Groups:         m.Groups,
-		Hosts:          rpcHosts,
-		TimePeriods:    rpcTimePeriods,
-	}, nil
+func maintenanceTypeName(typ MaintenanceType) string {
+	for _, n := range maintenanceTypeNames {
+		if n.typ == typ {
+			return n.name
+		}
+	}
+	return string(typ)
 }
 
-type TimeperiodType string
+type TagsEvalType = maintenance.TagsEvalType
 
 const (
-	TimeperiodTypeOnetimeOnly TimeperiodType = "0"
-	TimeperiodTypeDaily       TimeperiodType = "2"
-	TimeperiodTypeWeekly      TimeperiodType = "3"
-	TimeperiodTypeMonthly     TimeperiodType = "4"
+	TagsEvalTypeAndOr = maintenance.TagsEvalTypeAndOr
+	TagsEvalTypeOr    = maintenance.TagsEvalTypeOr
 )
 
-type TimePeriod struct {
-	TimeperiodID   string
-	Period         time.Duration
-	TimeperiodType TimeperiodType
-	StartDate      time.Time
-}
-
-func fromRPCTimePeriod(p rpc.TimePeriod) (TimePeriod, error) {
-	period, err := ParseSeconds(p.Period)
-	if err != nil {
-		return TimePeriod{}, err
-	}
-	startDate, err := ParseTimestamp(p.StartDate)
-	if err != nil {
-		return TimePeriod{}, err
-	}
+type TimeperiodType = maintenance.TimeperiodType
 
-	return TimePeriod{
-		TimeperiodID:   p.TimeperiodID,
-		Period:         time.Duration(period),
-		TimeperiodType: TimeperiodType(p.TimeperiodType),
-		StartDate:      time.Time(startDate),
-	}, nil
-}
+const (
+	TimeperiodTypeOnetimeOnly = maintenance.TimeperiodTypeOnetimeOnly
+	TimeperiodTypeDaily       = maintenance.TimeperiodTypeDaily
+	TimeperiodTypeWeekly      = maintenance.TimeperiodTypeWeekly
+	TimeperiodTypeMonthly     = maintenance.TimeperiodTypeMonthly
+)
 
-func toRPCTimePeriod(p TimePeriod) (rpc.TimePeriod, error) {
-	return rpc.TimePeriod{
-		TimeperiodID:   p.TimeperiodID,
-		Period:         Seconds(p.Period).String(),
-		TimeperiodType: string(p.TimeperiodType),
-		StartDate:      Timestamp(p.StartDate).String(),
-	}, nil
-}
+type TimePeriod = maintenance.TimePeriod
 
 func (c *myClient) GetMaintenances(ctx context.Context) ([]Maintenance, error) {
-	rm, err := c.inner.GetMaintenances(ctx)
-	if err != nil {
-		return nil, err
-	}
-	return slicex.FailableMap(rm, fromPRCMaintenance)
+	return maintenance.GetAll(ctx, c.inner)
 }
 
 func (c *myClient) GetMaintenanceByID(ctx context.Context, maintenanceID string) (*Maintenance, error) {
-	rm, err := c.inner.GetMaintenanceByID(ctx, maintenanceID)
-	if err != nil {
-		return nil, err
-	}
-	m, err := fromPRCMaintenance(*rm)
-	if err != nil {
-		return nil, err
-	}
-	return &m, nil
+	return maintenance.GetByID(ctx, c.inner, maintenanceID)
 }
 
 func (c *myClient) GetMaintenanceByNameFullMatch(ctx context.Context, name string) (*Maintenance, error) {
-	rm, err := c.inner.GetMaintenanceByNameFullMatch(ctx, name)
-	if err != nil {
-		return nil, err
-	}
-	m, err := fromPRCMaintenance(*rm)
-	if err != nil {
-		return nil, err
-	}
-	return &m, nil
+	return maintenance.GetByNameFullMatch(ctx, c.inner, name)
 }
 
 func (c *myClient) CreateMaintenance(ctx context.Context, m *Maintenance) error {
-	rm, err := toRPCMaintenance(*m)
-	if err != nil {
-		return err
-	}
-	if err := c.inner.CreateMaintenance(ctx, &rm); err != nil {
-		return err
-	}
-	m.MaintenaceID = rm.MaintenaceID
-	return nil
+	return maintenance.Create(ctx, c.inner, m)
 }
 
 func (c *myClient) UpdateMaintenance(ctx context.Context, m *Maintenance) error {
-	rm, err := toRPCMaintenance(*m)
-	if err != nil {
-		return err
-	}
-	return c.inner.UpdateMaintenance(ctx, &rm)
+	return maintenance.Update(ctx, c.inner, m)
 }
 
 func (c *myClient) GetMaintenanceIDsByIDs(ctx context.Context, maintenanceIDs []string) ([]string, error) {
-	return c.inner.GetMaintenanceIDsByIDs(ctx, maintenanceIDs)
+	return maintenance.GetIDsByIDs(ctx, c.inner, maintenanceIDs)
 }
 
 func (c *myClient) GetMaintenanceIDsByNamesFullMatch(ctx context.Context, names []string) ([]string, error) {
-	return c.inner.GetMaintenanceIDsByNamesFullMatch(ctx, names)
+	return maintenance.GetIDsByNamesFullMatch(ctx, c.inner, names)
 }
 
 func (c *myClient) DeleteMaintenancesByIDs(ctx context.Context, ids []string) (deletedIDs []string, err error) {
-	return c.inner.DeleteMaintenancesByIDs(ctx, ids)
+	return maintenance.DeleteByIDs(ctx, c.inner, ids)
 }
 
 type displayMaintenance struct {
-	MaintenaceID string              `json:"maintenanceid"`
-	Name         string              `json:"name"`
-	ActiveSince  displayTimestamp    `json:"active_since"`
-	ActiveTill   displayTimestamp    `json:"active_till"`
-	Description  string              `json:"description"`
-	Groups       []HostGroup         `json:"groups"`
-	Hosts        []displayHost       `json:"hosts"`
-	TimePeriods  []displayTimePeriod `json:"timeperiods"`
+	MaintenanceID   string              `json:"maintenanceid"`
+	Name            string              `json:"name"`
+	ActiveSince     displayTimestamp    `json:"active_since"`
+	ActiveTill      displayTimestamp    `json:"active_till"`
+	Description     string              `json:"description"`
+	MaintenanceType string              `json:"maintenance_type"`
+	Groups          []HostGroup         `json:"groups"`
+	Hosts           []displayHost       `json:"hosts"`
+	TimePeriods     []displayTimePeriod `json:"timeperiods"`
+	TagsEvalType    string              `json:"tags_evaltype"`
+	Tags            []string            `json:"tags"`
 }
 
 type displayHost struct {
@@ -221,20 +132,30 @@ type displayHost struct {
 }
 
 type displayTimePeriod struct {
-	Period    displayDuration  `json:"period"`
-	StartDate displayTimestamp `json:"start_date"`
+	TimeperiodID   string            `json:"timeperiodid"`
+	TimeperiodType string            `json:"timeperiod_type"`
+	Period         displayDuration   `json:"period"`
+	StartDate      *displayTimestamp `json:"start_date,omitempty"`
+	StartTime      string            `json:"start_time,omitempty"`
+	Every          int               `json:"every,omitempty"`
+	DayOfWeek      string            `json:"dayofweek,omitempty"`
+	Day            int               `json:"day,omitempty"`
+	Month          string            `json:"month,omitempty"`
 }
 
 func toDisplayMaintenance(m Maintenance) displayMaintenance {
 	return displayMaintenance{
-		MaintenaceID: m.MaintenaceID,
-		Name:         m.Name,
-		ActiveSince:  displayTimestamp(m.ActiveSince),
-		ActiveTill:   displayTimestamp(m.ActiveTill),
-		Description:  m.Description,
-		Groups:       m.Groups,
-		Hosts:        slicex.Map(m.Hosts, toDisplayHost),
-		TimePeriods:  slicex.Map(m.TimePeriods, toDisplayTimePeriod),
+		MaintenanceID:   m.MaintenanceID,
+		Name:            m.Name,
+		ActiveSince:     displayTimestamp(m.ActiveSince),
+		ActiveTill:      displayTimestamp(m.ActiveTill),
+		Description:     m.Description,
+		MaintenanceType: maintenanceTypeName(m.MaintenanceType),
+		Groups:          m.Groups,
+		Hosts:           slicex.Map(m.Hosts, toDisplayHost),
+		TimePeriods:     slicex.Map(m.TimePeriods, toDisplayTimePeriod),
+		TagsEvalType:    tagsEvalTypeName(m.TagsEvalType),
+		Tags:            slicex.Map(m.Tags, formatTag),
 	}
 }
 
@@ -250,10 +171,22 @@ func toDisplayHost(h Host) displayHost {
 }
 
 func toDisplayTimePeriod(tp TimePeriod) displayTimePeriod {
-	return displayTimePeriod{
-		Period:    displayDuration(tp.Period),
-		StartDate: displayTimestamp(tp.StartDate),
-	}
+	d := displayTimePeriod{
+		TimeperiodID:   tp.TimeperiodID,
+		TimeperiodType: timeperiodTypeName(tp.TimeperiodType),
+		Period:         displayDuration(tp.Period),
+	}
+	if tp.TimeperiodType == TimeperiodTypeOnetimeOnly {
+		startDate := displayTimestamp(tp.StartDate)
+		d.StartDate = &startDate
+		return d
+	}
+	d.StartTime = formatTimeOfDay(tp.StartTime)
+	d.Every = tp.Every
+	d.DayOfWeek = formatBitNames(int(tp.DayOfWeek), dayOfWeekNames)
+	d.Day = tp.Day
+	d.Month = formatBitNames(int(tp.Month), monthNames)
+	return d
 }
 
 // Timestamp is an alias to time.Time. Timestamp is encoded a string whose value
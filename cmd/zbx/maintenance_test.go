@@ -0,0 +1,209 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hnakamur/go-zabbix"
+	"github.com/hnakamur/go-zabbix/maintenance"
+	"github.com/hnakamur/go-zabbix/zabbixtest"
+)
+
+func newTestServerWithHosts(t *testing.T) *zabbixtest.Server {
+	t.Helper()
+	unsetZBXEnv(t)
+	s := zabbixtest.NewServer(zabbixtest.WithAPIToken("token1"))
+	t.Cleanup(s.Close)
+	groupID := s.AddHostGroup("Linux servers")
+	s.AddHost("server1", groupID)
+	s.AddHost("server2", groupID)
+	return s
+}
+
+func getTestMaintenance(t *testing.T, s *zabbixtest.Server, name string) *Maintenance {
+	t.Helper()
+	client, err := zabbix.NewClient(s.URL, zabbix.WithAPIToken("token1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	m, err := maintenance.GetByNameFullMatch(context.Background(), client, name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return m
+}
+
+func TestMaintenanceType(t *testing.T) {
+	create := []string{"mainte", "create", "-n", "m1", "-H", "server1", "-p", "1h"}
+	update := []string{"mainte", "update", "-n", "m1"}
+
+	testCases := []struct {
+		name     string
+		setup    [][]string
+		args     []string
+		wantType MaintenanceType
+		wantErr  string
+	}{
+		{
+			name:     "createDefault",
+			args:     create,
+			wantType: MaintenanceTypeWithData,
+		},
+		{
+			name:     "createNoData",
+			args:     append(create, "--type", "no-data"),
+			wantType: MaintenanceTypeNoData,
+		},
+		{
+			name:    "createNoDataWithTags",
+			args:    append(create, "--type", "no-data", "--tag", "service=mysql"),
+			wantErr: "problem tags can be set only for maintenance with data collection",
+		},
+		{
+			name:    "createInvalid",
+			args:    append(create, "--type", "none"),
+			wantErr: `invalid maintenance type: "none"`,
+		},
+		{
+			name:     "updateToNoData",
+			setup:    [][]string{create},
+			args:     append(update, "--type", "no-data"),
+			wantType: MaintenanceTypeNoData,
+		},
+		{
+			name:     "updateToWithData",
+			setup:    [][]string{append(create, "--type", "no-data")},
+			args:     append(update, "--type", "with-data"),
+			wantType: MaintenanceTypeWithData,
+		},
+		{
+			// The type is kept unless "--type" is set.
+			name:     "updateOtherProperty",
+			setup:    [][]string{append(create, "--type", "no-data")},
+			args:     append(update, "--desc", "updated"),
+			wantType: MaintenanceTypeNoData,
+		},
+		{
+			name:    "updateToNoDataWithTags",
+			setup:   [][]string{append(create, "--tag", "service=mysql")},
+			args:    append(update, "--type", "no-data"),
+			wantErr: "problem tags can be set only for maintenance with data collection",
+		},
+		{
+			name:     "updateToNoDataRemovingTags",
+			setup:    [][]string{append(create, "--tag", "service=mysql")},
+			args:     append(update, "--type", "no-data", "--tag", ""),
+			wantType: MaintenanceTypeNoData,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			s := newTestServerWithHosts(t)
+			for _, args := range tc.setup {
+				if _, err := runZBX(s, args...); err != nil {
+					t.Fatalf("setup %v: %v", args, err)
+				}
+			}
+			_, err := runZBX(s, tc.args...)
+			if tc.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+					t.Fatalf("error mismatch, got=%v, want=%s", err, tc.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := getTestMaintenance(t, s, "m1").MaintenanceType; got != tc.wantType {
+				t.Errorf("maintenance type mismatch, got=%s, want=%s", got, tc.wantType)
+			}
+		})
+	}
+}
+
+func TestAllMaintenanceTypeExpected(t *testing.T) {
+	testCases := []struct {
+		types    []MaintenanceType
+		expected MaintenanceType
+		want     bool
+	}{
+		{types: nil, expected: MaintenanceTypeNoData, want: true},
+		{types: []MaintenanceType{MaintenanceTypeNoData, MaintenanceTypeNoData},
+			expected: MaintenanceTypeNoData, want: true},
+		{types: []MaintenanceType{MaintenanceTypeNoData, MaintenanceTypeWithData},
+			expected: MaintenanceTypeNoData, want: false},
+		{types: []MaintenanceType{MaintenanceTypeWithData},
+			expected: MaintenanceTypeNoData, want: false},
+	}
+	for _, c := range testCases {
+		var hosts Hosts
+		for _, typ := range c.types {
+			hosts = append(hosts, Host{MaintenanceType: typ})
+		}
+		if got := hosts.allMaintenanceTypeExpected(c.expected); got != c.want {
+			t.Errorf("result mismatch, types=%v, expected=%s, got=%v, want=%v",
+				c.types, c.expected, got, c.want)
+		}
+	}
+}
+
+func TestWaitForMaintenanceInEffect(t *testing.T) {
+	wait := []string{"--wait", "--interval", "10ms"}
+
+	testCases := []struct {
+		name    string
+		setup   [][]string
+		args    []string
+		wantErr error
+	}{
+		{
+			name: "create",
+			args: append([]string{"mainte", "create", "-n", "m1", "-H", "server1", "-p", "1h",
+				"--type", "no-data"}, wait...),
+		},
+		{
+			name:  "updateType",
+			setup: [][]string{{"mainte", "create", "-n", "m1", "-H", "server1", "-p", "1h"}},
+			args:  append([]string{"mainte", "update", "-n", "m1", "--type", "no-data"}, wait...),
+		},
+		{
+			// server1 is in maintenance, but the type is of another
+			// maintenance.
+			name: "otherType",
+			setup: [][]string{{"mainte", "create", "-n", "m0", "-H", "server1", "-p", "1h",
+				"--type", "no-data"}},
+			args: append([]string{"mainte", "create", "-n", "m1", "-H", "server1", "-p", "1h"},
+				wait...),
+			wantErr: context.DeadlineExceeded,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			s := newTestServerWithHosts(t)
+			for _, args := range tc.setup {
+				if _, err := runZBX(s, args...); err != nil {
+					t.Fatalf("setup %v: %v", args, err)
+				}
+			}
+			ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+			defer cancel()
+			args := append([]string{"--url", s.URL, "--token", "token1"}, tc.args...)
+			output, err := runAppContext(ctx, args...)
+			if tc.wantErr != nil {
+				if !errors.Is(err, tc.wantErr) {
+					t.Fatalf("error mismatch, got=%v, want=%v", err, tc.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if want := "all hosts in specified maintenance become in effect status"; !strings.Contains(output, want) {
+				t.Errorf("output must contain %q, got=%s", want, output)
+			}
+		})
+	}
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"strings"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/hnakamur/go-zabbix"
+	"github.com/hnakamur/go-zabbix/internal/errlog"
+	"github.com/hnakamur/go-zabbix/internal/fileutil"
+)
+
+// sessionCache is a file which keeps a session ID for each pair of a Zabbix
+// URL and a username, so that repeated invocations of zbx do not log in
+// every time.
+type sessionCache struct {
+	path string
+	key  string
+}
+
+type sessionCacheEntry struct {
+	SessionID  string `json:"session_id"`
+	APIVersion string `json:"api_version"`
+}
+
+func newSessionCache(cCtx *cli.Context, path string) *sessionCache {
+	return &sessionCache{
+		path: path,
+		key:  cCtx.String("username") + "@" + strings.TrimSuffix(cCtx.String("url"), "/"),
+	}
+}
+
+// get returns the cached entry, or nil if it does not exist.
+func (c *sessionCache) get() (*sessionCacheEntry, error) {
+	entries, err := c.load()
+	if err != nil {
+		return nil, err
+	}
+	entry, ok := entries[c.key]
+	if !ok {
+		return nil, nil
+	}
+	return &entry, nil
+}
+
+// put saves entry, replacing the existing one.
+func (c *sessionCache) put(entry sessionCacheEntry) error {
+	entries, err := c.load()
+	if err != nil {
+		return err
+	}
+	if entries[c.key] == entry {
+		return nil
+	}
+	entries[c.key] = entry
+	data, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return err
+	}
+	return fileutil.WriteFileAtomic(c.path, data)
+}
+
+// load reads all entries in the cache file. A broken file is treated as empty,
+// since it is overwritten by put.
+func (c *sessionCache) load() (map[string]sessionCacheEntry, error) {
+	entries := make(map[string]sessionCacheEntry)
+	data, err := os.ReadFile(c.path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return entries, nil
+		}
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &entries); err != nil {
+		errlog.Warn("ignore broken session cache", "path", c.path, "err", err)
+		return make(map[string]sessionCacheEntry), nil
+	}
+	return entries, nil
+}
+
+// resumeSession sets the cached session ID to the client and validates it.
+// It returns false if the session has expired.
+func (c *myClient) resumeSession(ctx context.Context, sessionID string) (bool, error) {
+	c.inner.SetSessionID(sessionID)
+	if _, err := c.inner.CheckAuthentication(ctx); err != nil {
+		c.inner.SetSessionID("")
+		if zabbix.IsSessionExpired(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	c.loggedIn = true
+	return true, nil
+}
+
+// saveSession saves the current session ID to the cache.
+func (c *myClient) saveSession(ctx context.Context) error {
+	sessionID := c.inner.SessionID()
+	if sessionID == "" {
+		return nil
+	}
+	apiVer, err := c.inner.APIVersion(ctx)
+	if err != nil {
+		return err
+	}
+	return c.sessionCache.put(sessionCacheEntry{
+		SessionID:  sessionID,
+		APIVersion: apiVer.String(),
+	})
+}
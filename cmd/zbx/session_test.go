@@ -0,0 +1,186 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"golang.org/x/exp/slices"
+
+	"github.com/hnakamur/go-zabbix/zabbixtest"
+)
+
+func TestSessionCacheGetPut(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "zbx", "sessions.json")
+	c1 := &sessionCache{path: path, key: "Admin@http://zabbix1.example.com"}
+	c2 := &sessionCache{path: path, key: "Admin@http://zabbix2.example.com"}
+
+	if got, err := c1.get(); err != nil || got != nil {
+		t.Fatalf("want no entry for missing file, got=%v, err=%v", got, err)
+	}
+
+	entry1 := sessionCacheEntry{SessionID: "session1", APIVersion: "6.0.16"}
+	entry2 := sessionCacheEntry{SessionID: "session2", APIVersion: "7.0.0"}
+	if err := c1.put(entry1); err != nil {
+		t.Fatal(err)
+	}
+	if err := c2.put(entry2); err != nil {
+		t.Fatal(err)
+	}
+	for _, c := range []struct {
+		cache *sessionCache
+		want  sessionCacheEntry
+	}{
+		{cache: c1, want: entry1},
+		{cache: c2, want: entry2},
+	} {
+		got, err := c.cache.get()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got == nil || *got != c.want {
+			t.Errorf("entry mismatch, key=%s, got=%v, want=%v", c.cache.key, got, c.want)
+		}
+	}
+
+	if runtime.GOOS != "windows" {
+		fi, err := os.Stat(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got, want := fi.Mode().Perm(), os.FileMode(0o600); got != want {
+			t.Errorf("file mode mismatch, got=%s, want=%s", got, want)
+		}
+	}
+
+	// A broken file is treated as empty and overwritten.
+	if err := os.WriteFile(path, []byte("{broken"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := c1.get(); err != nil || got != nil {
+		t.Fatalf("want no entry for broken file, got=%v, err=%v", got, err)
+	}
+	if err := c1.put(entry1); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := c1.get(); err != nil || got == nil || *got != entry1 {
+		t.Errorf("entry mismatch after overwriting broken file, got=%v, err=%v", got, err)
+	}
+}
+
+func TestSessionCacheLogin(t *testing.T) {
+	unsetZBXEnv(t)
+	passwords := map[string]string{"user1": "password1", "user2": "password2"}
+	s := zabbixtest.NewServer(zabbixtest.WithUser("user1", passwords["user1"]),
+		zabbixtest.WithUser("user2", passwords["user2"]))
+	defer s.Close()
+	path := filepath.Join(t.TempDir(), "sessions.json")
+
+	// run runs "mainte get" with the session cache, and returns the methods
+	// sent for authentication.
+	run := func(t *testing.T, url, username string) []string {
+		t.Helper()
+		n := len(s.Requests())
+		if _, err := runApp("--url", url, "--username", username, "--password", passwords[username],
+			"--session-cache", path, "mainte", "get"); err != nil {
+			t.Fatal(err)
+		}
+		var methods []string
+		for _, req := range s.Requests()[n:] {
+			switch req.Method {
+			case "user.login", "user.logout", "user.checkAuthentication":
+				methods = append(methods, req.Method)
+			}
+		}
+		return methods
+	}
+	keys := func(t *testing.T) []string {
+		t.Helper()
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var entries map[string]sessionCacheEntry
+		if err := json.Unmarshal(data, &entries); err != nil {
+			t.Fatal(err)
+		}
+		var keys []string
+		for k := range entries {
+			keys = append(keys, k)
+		}
+		slices.Sort(keys)
+		return keys
+	}
+
+	testCases := []struct {
+		name string
+		// expire expires the sessions before running.
+		expire      bool
+		url         string
+		username    string
+		wantMethods []string
+		wantKeys    []string
+	}{
+		{
+			name:        "login",
+			url:         s.URL,
+			username:    "user1",
+			wantMethods: []string{"user.login"},
+			wantKeys:    []string{"user1@" + s.URL},
+		},
+		{
+			// The session is not logged out but kept in the cache.
+			name:        "resume",
+			url:         s.URL,
+			username:    "user1",
+			wantMethods: []string{"user.checkAuthentication"},
+			wantKeys:    []string{"user1@" + s.URL},
+		},
+		{
+			name:        "trailingSlash",
+			url:         s.URL + "/",
+			username:    "user1",
+			wantMethods: []string{"user.checkAuthentication"},
+			wantKeys:    []string{"user1@" + s.URL},
+		},
+		{
+			name:        "anotherUser",
+			url:         s.URL,
+			username:    "user2",
+			wantMethods: []string{"user.login"},
+			wantKeys:    []string{"user1@" + s.URL, "user2@" + s.URL},
+		},
+		{
+			name:        "expired",
+			expire:      true,
+			url:         s.URL,
+			username:    "user1",
+			wantMethods: []string{"user.checkAuthentication", "user.login"},
+			wantKeys:    []string{"user1@" + s.URL, "user2@" + s.URL},
+		},
+		{
+			// The session got by the new login is cached.
+			name:        "resumeAfterExpired",
+			url:         s.URL,
+			username:    "user1",
+			wantMethods: []string{"user.checkAuthentication"},
+			wantKeys:    []string{"user1@" + s.URL, "user2@" + s.URL},
+		},
+	}
+	// The cases are run in order, since they depend on the cache file.
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.expire {
+				s.ExpireSessions()
+			}
+			if got := run(t, tc.url, tc.username); !slices.Equal(got, tc.wantMethods) {
+				t.Errorf("methods mismatch, got=%v, want=%v", got, tc.wantMethods)
+			}
+			if got := keys(t); !slices.Equal(got, tc.wantKeys) {
+				t.Errorf("keys mismatch, got=%v, want=%v", got, tc.wantKeys)
+			}
+		})
+	}
+}
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/hnakamur/go-zabbix/maintenance"
+)
+
+type ProblemTag = maintenance.ProblemTag
+
+type TagOperator = maintenance.TagOperator
+
+const (
+	TagOperatorEquals   = maintenance.TagOperatorEquals
+	TagOperatorContains = maintenance.TagOperatorContains
+)
+
+// tagOperatorSymbols is the symbols of tag operators used in "--tag".
+var tagOperatorSymbols = []struct {
+	op     TagOperator
+	symbol string
+}{
+	{TagOperatorEquals, "="},
+	{TagOperatorContains, "~"},
+}
+
+var tagsEvalTypeNames = []struct {
+	evalType TagsEvalType
+	name     string
+}{
+	{TagsEvalTypeAndOr, "and-or"},
+	{TagsEvalTypeOr, "or"},
+}
+
+// applyTagFlags sets the problem tags specified with "--tag" and
+// "--tags-evaltype" to m. An empty "--tag" set just once removes all tags.
+func applyTagFlags(cCtx *cli.Context, m *Maintenance) error {
+	if cCtx.IsSet("tag") {
+		values := cCtx.StringSlice("tag")
+		if len(values) == 1 && values[0] == "" {
+			m.Tags = []ProblemTag{}
+		} else {
+			tags, err := parseTags(values)
+			if err != nil {
+				return err
+			}
+			m.Tags = tags
+		}
+	}
+	if cCtx.IsSet("tags-evaltype") {
+		m.TagsEvalType = cCtx.Generic("tags-evaltype").(*tagsEvalTypeValue).evalType
+	}
+
+	if len(m.Tags) > 0 && m.MaintenanceType != MaintenanceTypeWithData {
+		return errors.New(`problem tags can be set only for maintenance with data collection (remove them with "--tag ''")`)
+	}
+	return nil
+}
+
+// parseTags parses tags in "key=value" for the equals operator or
+// "key~value" for the contains operator.
+func parseTags(values []string) ([]ProblemTag, error) {
+	tags := make([]ProblemTag, len(values))
+	for i, value := range values {
+		pos := strings.IndexAny(value, "=~")
+		if pos <= 0 {
+			return nil, fmt.Errorf(`invalid tag %q, must be "key=value" or "key~value"`, value)
+		}
+		tags[i] = ProblemTag{Tag: value[:pos], Value: value[pos+1:]}
+		for _, s := range tagOperatorSymbols {
+			if s.symbol == value[pos:pos+1] {
+				tags[i].Operator = s.op
+			}
+		}
+	}
+	return tags, nil
+}
+
+// formatTag formats tag in the same way as "--tag".
+func formatTag(tag ProblemTag) string {
+	for _, s := range tagOperatorSymbols {
+		if s.op == tag.Operator {
+			return tag.Tag + s.symbol + tag.Value
+		}
+	}
+	return fmt.Sprintf("%s(operator=%s)%s", tag.Tag, tag.Operator, tag.Value)
+}
+
+type tagsEvalTypeValue struct {
+	evalType TagsEvalType
+}
+
+func (v *tagsEvalTypeValue) Set(value string) error {
+	for _, n := range tagsEvalTypeNames {
+		if n.name == value {
+			v.evalType = n.evalType
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid tags evaluation type: %q", value)
+}
+
+func (v *tagsEvalTypeValue) String() string {
+	if v.evalType == "" {
+		return ""
+	}
+	return tagsEvalTypeName(v.evalType)
+}
+
+func tagsEvalTypeName(evalType TagsEvalType) string {
+	for _, n := range tagsEvalTypeNames {
+		if n.evalType == evalType {
+			return n.name
+		}
+	}
+	return string(evalType)
+}
@@ -0,0 +1,170 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/urfave/cli/v2"
+)
+
+func TestParseTags(t *testing.T) {
+	testCases := []struct {
+		values  []string
+		want    []ProblemTag
+		wantErr bool
+	}{
+		{
+			values: []string{"service=mysql", "env~prod"},
+			want: []ProblemTag{
+				{Tag: "service", Operator: TagOperatorEquals, Value: "mysql"},
+				{Tag: "env", Operator: TagOperatorContains, Value: "prod"},
+			},
+		},
+		{
+			// The first "=" or "~" is the operator.
+			values: []string{"url=a~b", "path~a=b", "empty="},
+			want: []ProblemTag{
+				{Tag: "url", Operator: TagOperatorEquals, Value: "a~b"},
+				{Tag: "path", Operator: TagOperatorContains, Value: "a=b"},
+				{Tag: "empty", Operator: TagOperatorEquals, Value: ""},
+			},
+		},
+		{values: []string{"service"}, wantErr: true},
+		{values: []string{"=mysql"}, wantErr: true},
+		{values: []string{"~mysql"}, wantErr: true},
+		{values: []string{"service=mysql", ""}, wantErr: true},
+	}
+	for _, c := range testCases {
+		got, err := parseTags(c.values)
+		if c.wantErr {
+			if err == nil {
+				t.Errorf("want error for %q, got=%+v", c.values, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("unexpected error for %q: %v", c.values, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, c.want) {
+			t.Errorf("tags mismatch, values=%q,\n got=%+v,\nwant=%+v", c.values, got, c.want)
+		}
+		for i, tag := range got {
+			if s := formatTag(tag); s != c.values[i] {
+				t.Errorf("formatted tag mismatch, got=%s, want=%s", s, c.values[i])
+			}
+		}
+	}
+}
+
+func TestApplyTagFlags(t *testing.T) {
+	tag := ProblemTag{Tag: "service", Operator: TagOperatorEquals, Value: "mysql"}
+	withData := Maintenance{
+		MaintenanceType: MaintenanceTypeWithData,
+		TagsEvalType:    TagsEvalTypeAndOr,
+		Tags:            []ProblemTag{tag},
+	}
+	noData := Maintenance{
+		MaintenanceType: MaintenanceTypeNoData,
+		TagsEvalType:    TagsEvalTypeAndOr,
+	}
+
+	testCases := []struct {
+		name    string
+		m       Maintenance
+		args    []string
+		want    Maintenance
+		wantErr string
+	}{
+		{
+			name: "noFlags",
+			m:    withData,
+			want: withData,
+		},
+		{
+			name: "replace",
+			m:    withData,
+			args: []string{"--tag", "env~prod", "--tag", "role=db"},
+			want: Maintenance{
+				MaintenanceType: MaintenanceTypeWithData,
+				TagsEvalType:    TagsEvalTypeAndOr,
+				Tags: []ProblemTag{
+					{Tag: "env", Operator: TagOperatorContains, Value: "prod"},
+					{Tag: "role", Operator: TagOperatorEquals, Value: "db"},
+				},
+			},
+		},
+		{
+			name: "remove",
+			m:    withData,
+			args: []string{"--tag", ""},
+			want: Maintenance{
+				MaintenanceType: MaintenanceTypeWithData,
+				TagsEvalType:    TagsEvalTypeAndOr,
+				Tags:            []ProblemTag{},
+			},
+		},
+		{
+			name: "evalTypeOr",
+			m:    withData,
+			args: []string{"--tags-evaltype", "or"},
+			want: Maintenance{
+				MaintenanceType: MaintenanceTypeWithData,
+				TagsEvalType:    TagsEvalTypeOr,
+				Tags:            []ProblemTag{tag},
+			},
+		},
+		{
+			name:    "invalidEvalType",
+			m:       withData,
+			args:    []string{"--tags-evaltype", "and"},
+			wantErr: `invalid tags evaluation type: "and"`,
+		},
+		{
+			name:    "malformedTag",
+			m:       withData,
+			args:    []string{"--tag", "service"},
+			wantErr: `invalid tag "service", must be "key=value" or "key~value"`,
+		},
+		{
+			name:    "noData",
+			m:       noData,
+			args:    []string{"--tag", "service=mysql"},
+			wantErr: "problem tags can be set only for maintenance with data collection",
+		},
+		{
+			// Tags left from the maintenance type with data collection
+			// must be removed explicitly.
+			name:    "noDataWithExistingTags",
+			m:       Maintenance{MaintenanceType: MaintenanceTypeNoData, Tags: []ProblemTag{tag}},
+			wantErr: "problem tags can be set only for maintenance with data collection",
+		},
+		{
+			name: "noDataRemove",
+			m:    Maintenance{MaintenanceType: MaintenanceTypeNoData, Tags: []ProblemTag{tag}},
+			args: []string{"--tag", ""},
+			want: Maintenance{MaintenanceType: MaintenanceTypeNoData, Tags: []ProblemTag{}},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			m := tc.m
+			err := runWithUpdateFlags(t, tc.args, func(cCtx *cli.Context) error {
+				return applyTagFlags(cCtx, &m)
+			})
+			if tc.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+					t.Fatalf("error mismatch, got=%v, want=%s", err, tc.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(m, tc.want) {
+				t.Errorf("maintenance mismatch,\n got=%+v,\nwant=%+v", m, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,369 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/urfave/cli/v2"
+	"golang.org/x/exp/slices"
+
+	"github.com/hnakamur/go-zabbix/maintenance"
+)
+
+type DayOfWeek = maintenance.DayOfWeek
+
+type Month = maintenance.Month
+
+var timeperiodTypeNames = []struct {
+	typ  TimeperiodType
+	name string
+}{
+	{TimeperiodTypeOnetimeOnly, "one-time"},
+	{TimeperiodTypeDaily, "daily"},
+	{TimeperiodTypeWeekly, "weekly"},
+	{TimeperiodTypeMonthly, "monthly"},
+}
+
+// dayOfWeekNames is the names of days of the week in the order of the bits.
+var dayOfWeekNames = []string{"mon", "tue", "wed", "thu", "fri", "sat", "sun"}
+
+// monthNames is the names of months in the order of the bits.
+var monthNames = []string{"jan", "feb", "mar", "apr", "may", "jun",
+	"jul", "aug", "sep", "oct", "nov", "dec"}
+
+// timePeriodFlags returns the flags for recurring time periods, which are
+// shared by "mainte create" and "mainte update".
+func timePeriodFlags() []cli.Flag {
+	return []cli.Flag{
+		&cli.GenericFlag{
+			Name:  "timeperiod-type",
+			Value: &timeperiodTypeValue{typ: TimeperiodTypeOnetimeOnly},
+			Usage: "type of time period: one-time, daily, weekly or monthly",
+		},
+		&cli.GenericFlag{
+			Name:  "start-time",
+			Value: &timeOfDayValue{},
+			Usage: "time of day when a recurring maintenance starts in HH:MM",
+		},
+		&cli.IntFlag{
+			Name: "every",
+			Usage: "interval in days for daily, in weeks for weekly, or week of month " +
+				`for monthly with "--day-of-week" (1-4, or 5 for the last week)`,
+		},
+		&cli.GenericFlag{
+			Name:  "day-of-week",
+			Value: &dayOfWeekValue{},
+			Usage: `comma separated days of week for weekly or monthly, e.g. "mon,wed,fri"`,
+		},
+		&cli.IntFlag{
+			Name:  "day",
+			Usage: "day of month for monthly",
+		},
+		&cli.GenericFlag{
+			Name:  "month",
+			Value: &monthValue{},
+			Usage: `comma separated months for monthly, e.g. "jan,jul" or "all"`,
+		},
+	}
+}
+
+// newTimePeriod returns a new time period built from the flags. The start
+// date of a one-time period defaults to now.
+func newTimePeriod(cCtx *cli.Context) (TimePeriod, error) {
+	tp := TimePeriod{
+		Period:         cCtx.Duration("period"),
+		TimeperiodType: TimeperiodTypeOnetimeOnly,
+	}
+	if err := applyTimePeriodFlags(cCtx, &tp); err != nil {
+		return TimePeriod{}, err
+	}
+	if tp.TimeperiodType == TimeperiodTypeOnetimeOnly {
+		if t := cCtx.Timestamp("start-date"); t != nil {
+			tp.StartDate = *t
+		} else {
+			tp.StartDate = time.Now().Truncate(time.Minute)
+		}
+	}
+	return tp, nil
+}
+
+// updateTimePeriods adds, updates or removes a time period of m as specified
+// with the flags of "mainte update". The other time periods are kept as is.
+func updateTimePeriods(cCtx *cli.Context, m *Maintenance) error {
+	if cCtx.Bool("add-timeperiod") {
+		if cCtx.Bool("remove-timeperiod") || cCtx.IsSet("timeperiod-id") || cCtx.IsSet("timeperiod-index") {
+			return errors.New(`"--add-timeperiod" cannot be used with "--remove-timeperiod", "--timeperiod-id" or "--timeperiod-index"`)
+		}
+		if cCtx.Duration("period") == 0 {
+			return errors.New(`"--period" must be set with "--add-timeperiod"`)
+		}
+		tp, err := newTimePeriod(cCtx)
+		if err != nil {
+			return err
+		}
+		m.TimePeriods = append(m.TimePeriods, tp)
+		return nil
+	}
+
+	var setFlags []string
+	for _, name := range []string{"start-date", "period", "timeperiod-type", "start-time",
+		"every", "day-of-week", "day", "month"} {
+		if cCtx.IsSet(name) {
+			setFlags = append(setFlags, name)
+		}
+	}
+	if !cCtx.Bool("remove-timeperiod") && len(setFlags) == 0 {
+		return nil
+	}
+	i, err := selectTimePeriod(cCtx, m.TimePeriods)
+	if err != nil {
+		return err
+	}
+
+	if cCtx.Bool("remove-timeperiod") {
+		if len(setFlags) > 0 {
+			return fmt.Errorf(`"--%s" cannot be used with "--remove-timeperiod"`, setFlags[0])
+		}
+		if len(m.TimePeriods) == 1 {
+			return errors.New("cannot remove the only time period of maintenance")
+		}
+		m.TimePeriods = slices.Delete(m.TimePeriods, i, i+1)
+		return nil
+	}
+
+	tp := &m.TimePeriods[i]
+	if t := cCtx.Timestamp("start-date"); t != nil {
+		tp.StartDate = *t
+	}
+	if d := cCtx.Duration("period"); d != 0 {
+		tp.Period = d
+	}
+	return applyTimePeriodFlags(cCtx, tp)
+}
+
+// selectTimePeriod returns the index of the time period selected with
+// "--timeperiod-id" or "--timeperiod-index". They can be omitted if there is
+// only one time period.
+func selectTimePeriod(cCtx *cli.Context, tps []TimePeriod) (int, error) {
+	switch {
+	case cCtx.IsSet("timeperiod-id") && cCtx.IsSet("timeperiod-index"):
+		return 0, errors.New(`"--timeperiod-id" and "--timeperiod-index" cannot be used together`)
+	case cCtx.IsSet("timeperiod-id"):
+		id := cCtx.String("timeperiod-id")
+		i := slices.IndexFunc(tps, func(tp TimePeriod) bool {
+			return tp.TimeperiodID == id
+		})
+		if i == -1 {
+			return 0, fmt.Errorf("time period not found in maintenance: %s", id)
+		}
+		return i, nil
+	case cCtx.IsSet("timeperiod-index"):
+		index := cCtx.Int("timeperiod-index")
+		if index < 1 || index > len(tps) {
+			return 0, fmt.Errorf(`"--timeperiod-index" must be between 1 and %d, got %d`, len(tps), index)
+		}
+		return index - 1, nil
+	case len(tps) == 1:
+		return 0, nil
+	default:
+		return 0, fmt.Errorf(`"--timeperiod-id" or "--timeperiod-index" must be set since maintenance has %d time periods`, len(tps))
+	}
+}
+
+// applyTimePeriodFlags sets the values of the flags returned by
+// timePeriodFlags to tp and validates it.
+func applyTimePeriodFlags(cCtx *cli.Context, tp *TimePeriod) error {
+	if cCtx.IsSet("timeperiod-type") {
+		typ := cCtx.Generic("timeperiod-type").(*timeperiodTypeValue).typ
+		if typ != tp.TimeperiodType {
+			// Drop the values for the previous type.
+			*tp = TimePeriod{
+				TimeperiodID:   tp.TimeperiodID,
+				Period:         tp.Period,
+				TimeperiodType: typ,
+				StartDate:      tp.StartDate,
+			}
+		}
+	}
+	if cCtx.IsSet("start-time") {
+		tp.StartTime = cCtx.Generic("start-time").(*timeOfDayValue).d
+	}
+	if cCtx.IsSet("every") {
+		tp.Every = cCtx.Int("every")
+	}
+	if cCtx.IsSet("day-of-week") {
+		tp.DayOfWeek = cCtx.Generic("day-of-week").(*dayOfWeekValue).days
+		if tp.TimeperiodType == TimeperiodTypeMonthly && !cCtx.IsSet("day") {
+			tp.Day = 0
+		}
+	}
+	if cCtx.IsSet("day") {
+		tp.Day = cCtx.Int("day")
+		if tp.TimeperiodType == TimeperiodTypeMonthly && !cCtx.IsSet("day-of-week") {
+			// "every" is the week of month for "day-of-week".
+			tp.DayOfWeek = 0
+			if !cCtx.IsSet("every") {
+				tp.Every = 0
+			}
+		}
+	}
+	if cCtx.IsSet("month") {
+		tp.Month = cCtx.Generic("month").(*monthValue).months
+	}
+
+	if tp.TimeperiodType == TimeperiodTypeOnetimeOnly {
+		for _, name := range []string{"start-time", "every", "day-of-week", "day", "month"} {
+			if cCtx.IsSet(name) {
+				return fmt.Errorf(`"--%s" cannot be used for one-time time periods`, name)
+			}
+		}
+		return nil
+	}
+	if cCtx.IsSet("start-date") {
+		return errors.New(`"--start-date" can be used only for one-time time periods`)
+	}
+	return validateRecurringTimePeriod(tp)
+}
+
+// validateRecurringTimePeriod validates tp. Errors refer to the values by
+// names without "--", since they are used for both the flags and the keys in
+// the file of "mainte apply".
+func validateRecurringTimePeriod(tp *TimePeriod) error {
+	if tp.Every < 0 {
+		return errors.New(`"every" must be positive`)
+	}
+	switch tp.TimeperiodType {
+	case TimeperiodTypeWeekly:
+		if tp.DayOfWeek == 0 {
+			return errors.New(`"day-of-week" must be set for weekly time periods`)
+		}
+	case TimeperiodTypeMonthly:
+		if tp.Month == 0 {
+			return errors.New(`"month" must be set for monthly time periods`)
+		}
+		if (tp.Day == 0) == (tp.DayOfWeek == 0) {
+			return errors.New(`either "day" or "day-of-week" must be set for monthly time periods`)
+		}
+		if tp.Day < 0 || tp.Day > 31 {
+			return fmt.Errorf(`"day" must be between 1 and 31, got %d`, tp.Day)
+		}
+		if tp.DayOfWeek != 0 && tp.Every > 5 {
+			return fmt.Errorf(`"every" must be between 1 and 5 with "day-of-week", got %d`, tp.Every)
+		}
+	}
+	return nil
+}
+
+type timeperiodTypeValue struct {
+	typ TimeperiodType
+}
+
+func (v *timeperiodTypeValue) Set(value string) error {
+	for _, n := range timeperiodTypeNames {
+		if n.name == value {
+			v.typ = n.typ
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid time period type: %q", value)
+}
+
+func (v *timeperiodTypeValue) String() string {
+	return timeperiodTypeName(v.typ)
+}
+
+func timeperiodTypeName(typ TimeperiodType) string {
+	for _, n := range timeperiodTypeNames {
+		if n.typ == typ {
+			return n.name
+		}
+	}
+	return string(typ)
+}
+
+// timeOfDayValue is a time of day in HH:MM.
+type timeOfDayValue struct {
+	d time.Duration
+}
+
+func (v *timeOfDayValue) Set(value string) error {
+	t, err := time.Parse("15:04", value)
+	if err != nil {
+		return fmt.Errorf("invalid time of day %q, must be in HH:MM", value)
+	}
+	v.d = time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute
+	return nil
+}
+
+func (v *timeOfDayValue) String() string {
+	return formatTimeOfDay(v.d)
+}
+
+func formatTimeOfDay(d time.Duration) string {
+	return fmt.Sprintf("%02d:%02d", int(d/time.Hour), int(d%time.Hour/time.Minute))
+}
+
+type dayOfWeekValue struct {
+	days DayOfWeek
+}
+
+func (v *dayOfWeekValue) Set(value string) error {
+	bits, err := parseBitNames(value, dayOfWeekNames)
+	if err != nil {
+		return fmt.Errorf("invalid day of week: %w", err)
+	}
+	v.days = DayOfWeek(bits)
+	return nil
+}
+
+func (v *dayOfWeekValue) String() string {
+	return formatBitNames(int(v.days), dayOfWeekNames)
+}
+
+type monthValue struct {
+	months Month
+}
+
+func (v *monthValue) Set(value string) error {
+	bits, err := parseBitNames(value, monthNames)
+	if err != nil {
+		return fmt.Errorf("invalid month: %w", err)
+	}
+	v.months = Month(bits)
+	return nil
+}
+
+func (v *monthValue) String() string {
+	return formatBitNames(int(v.months), monthNames)
+}
+
+// parseBitNames parses comma separated names into a bitmask where the n-th
+// bit is for names[n]. "all" sets all bits.
+func parseBitNames(value string, names []string) (int, error) {
+	var bits int
+	for _, s := range strings.Split(value, ",") {
+		s = strings.ToLower(strings.TrimSpace(s))
+		if s == "all" {
+			bits |= 1<<len(names) - 1
+			continue
+		}
+		i := slices.Index(names, s)
+		if i == -1 {
+			return 0, fmt.Errorf("%q is not one of %s", s, strings.Join(names, ","))
+		}
+		bits |= 1 << i
+	}
+	return bits, nil
+}
+
+func formatBitNames(bits int, names []string) string {
+	var set []string
+	for i, name := range names {
+		if bits&(1<<i) != 0 {
+			set = append(set, name)
+		}
+	}
+	return strings.Join(set, ",")
+}
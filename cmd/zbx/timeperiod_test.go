@@ -0,0 +1,386 @@
+package main
+
+import (
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/hnakamur/go-zabbix/maintenance"
+)
+
+// runWithUpdateFlags parses args with the flags of "mainte update", and calls
+// action with the context.
+func runWithUpdateFlags(t *testing.T, args []string, action cli.ActionFunc) error {
+	t.Helper()
+	var flags []cli.Flag
+	for _, cmd := range newApp().Commands {
+		if cmd.Name != "mainte" {
+			continue
+		}
+		for _, sub := range cmd.Subcommands {
+			if sub.Name == "update" {
+				flags = sub.Flags
+			}
+		}
+	}
+	if flags == nil {
+		t.Fatal(`"mainte update" command not found`)
+	}
+	app := &cli.App{
+		Name:      "test",
+		Flags:     flags,
+		Action:    action,
+		Writer:    io.Discard,
+		ErrWriter: io.Discard,
+	}
+	return app.Run(append([]string{"test"}, args...))
+}
+
+func TestApplyTimePeriodFlags(t *testing.T) {
+	startDate := time.Date(2030, 1, 2, 3, 4, 0, 0, time.Local)
+	oneTime := TimePeriod{
+		TimeperiodID:   "1",
+		Period:         time.Hour,
+		TimeperiodType: TimeperiodTypeOnetimeOnly,
+		StartDate:      startDate,
+	}
+	daily := TimePeriod{
+		TimeperiodID:   "1",
+		Period:         time.Hour,
+		TimeperiodType: TimeperiodTypeDaily,
+		StartTime:      2 * time.Hour,
+		Every:          1,
+	}
+	weekly := TimePeriod{
+		TimeperiodID:   "1",
+		Period:         time.Hour,
+		TimeperiodType: TimeperiodTypeWeekly,
+		Every:          2,
+		DayOfWeek:      maintenance.DayOfWeekMonday | maintenance.DayOfWeekFriday,
+	}
+	monthlyByDay := TimePeriod{
+		TimeperiodID:   "1",
+		Period:         time.Hour,
+		TimeperiodType: TimeperiodTypeMonthly,
+		Day:            15,
+		Month:          maintenance.MonthJanuary | maintenance.MonthJuly,
+	}
+	monthlyByWeek := TimePeriod{
+		TimeperiodID:   "1",
+		Period:         time.Hour,
+		TimeperiodType: TimeperiodTypeMonthly,
+		Every:          5,
+		DayOfWeek:      maintenance.DayOfWeekSunday,
+		Month:          maintenance.MonthDecember,
+	}
+
+	testCases := []struct {
+		name    string
+		tp      TimePeriod
+		args    []string
+		want    TimePeriod
+		wantErr string
+	}{
+		{
+			name: "oneTimeToDaily",
+			tp:   oneTime,
+			args: []string{"--timeperiod-type", "daily", "--start-time", "02:00", "--every", "1"},
+			want: TimePeriod{TimeperiodID: "1", Period: time.Hour, TimeperiodType: TimeperiodTypeDaily,
+				StartDate: startDate, StartTime: 2 * time.Hour, Every: 1},
+		},
+		{
+			name: "dailyToWeekly",
+			tp:   daily,
+			args: []string{"--timeperiod-type", "weekly", "--day-of-week", "mon,fri", "--every", "2"},
+			want: TimePeriod{TimeperiodID: "1", Period: time.Hour, TimeperiodType: TimeperiodTypeWeekly,
+				Every: 2, DayOfWeek: maintenance.DayOfWeekMonday | maintenance.DayOfWeekFriday},
+		},
+		{
+			name: "weeklyToMonthly",
+			tp:   weekly,
+			args: []string{"--timeperiod-type", "monthly", "--day", "15", "--month", "jan,jul"},
+			want: monthlyByDay,
+		},
+		{
+			name: "monthlyToOneTime",
+			tp:   monthlyByWeek,
+			args: []string{"--timeperiod-type", "one-time"},
+			want: TimePeriod{TimeperiodID: "1", Period: time.Hour, TimeperiodType: TimeperiodTypeOnetimeOnly},
+		},
+		{
+			name: "sameType",
+			tp:   weekly,
+			args: []string{"--timeperiod-type", "weekly", "--start-time", "23:30"},
+			want: TimePeriod{TimeperiodID: "1", Period: time.Hour, TimeperiodType: TimeperiodTypeWeekly,
+				StartTime: 23*time.Hour + 30*time.Minute, Every: 2,
+				DayOfWeek: maintenance.DayOfWeekMonday | maintenance.DayOfWeekFriday},
+		},
+		{
+			name: "monthlyDayToDayOfWeek",
+			tp:   monthlyByDay,
+			args: []string{"--day-of-week", "sun", "--every", "5", "--month", "dec"},
+			want: monthlyByWeek,
+		},
+		{
+			// "every" is the week of month for "day-of-week", so it is
+			// cleared with it.
+			name: "monthlyDayOfWeekToDay",
+			tp:   monthlyByWeek,
+			args: []string{"--day", "15", "--month", "jan,jul"},
+			want: monthlyByDay,
+		},
+		{
+			name: "monthAll",
+			tp:   monthlyByDay,
+			args: []string{"--month", "all"},
+			want: TimePeriod{TimeperiodID: "1", Period: time.Hour, TimeperiodType: TimeperiodTypeMonthly,
+				Day: 15, Month: 1<<12 - 1},
+		},
+		{
+			name:    "oneTimeWithEvery",
+			tp:      oneTime,
+			args:    []string{"--every", "2"},
+			wantErr: `"--every" cannot be used for one-time time periods`,
+		},
+		{
+			name:    "recurringWithStartDate",
+			tp:      daily,
+			args:    []string{"--start-date", "2030-01-01T00:00"},
+			wantErr: `"--start-date" can be used only for one-time time periods`,
+		},
+		{
+			name:    "negativeEvery",
+			tp:      daily,
+			args:    []string{"--every", "-1"},
+			wantErr: `"every" must be positive`,
+		},
+		{
+			name:    "weeklyWithoutDayOfWeek",
+			tp:      daily,
+			args:    []string{"--timeperiod-type", "weekly"},
+			wantErr: `"day-of-week" must be set for weekly time periods`,
+		},
+		{
+			name:    "monthlyWithoutMonth",
+			tp:      weekly,
+			args:    []string{"--timeperiod-type", "monthly", "--day", "1"},
+			wantErr: `"month" must be set for monthly time periods`,
+		},
+		{
+			name:    "monthlyWithoutDay",
+			tp:      weekly,
+			args:    []string{"--timeperiod-type", "monthly", "--month", "all"},
+			wantErr: `either "day" or "day-of-week" must be set for monthly time periods`,
+		},
+		{
+			name:    "monthlyWithDayAndDayOfWeek",
+			tp:      monthlyByDay,
+			args:    []string{"--day", "1", "--day-of-week", "mon"},
+			wantErr: `either "day" or "day-of-week" must be set for monthly time periods`,
+		},
+		{
+			name:    "monthlyDayOutOfRange",
+			tp:      monthlyByDay,
+			args:    []string{"--day", "32"},
+			wantErr: `"day" must be between 1 and 31, got 32`,
+		},
+		{
+			name:    "monthlyWeekOutOfRange",
+			tp:      monthlyByWeek,
+			args:    []string{"--every", "6"},
+			wantErr: `"every" must be between 1 and 5 with "day-of-week", got 6`,
+		},
+		{
+			name:    "invalidDayOfWeek",
+			tp:      weekly,
+			args:    []string{"--day-of-week", "mon,foo"},
+			wantErr: `invalid day of week: "foo" is not one of mon,tue,wed,thu,fri,sat,sun`,
+		},
+		{
+			name:    "invalidMonth",
+			tp:      monthlyByDay,
+			args:    []string{"--month", "january"},
+			wantErr: `invalid month: "january" is not one of`,
+		},
+		{
+			name:    "invalidType",
+			tp:      daily,
+			args:    []string{"--timeperiod-type", "yearly"},
+			wantErr: `invalid time period type: "yearly"`,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tp := tc.tp
+			err := runWithUpdateFlags(t, tc.args, func(cCtx *cli.Context) error {
+				return applyTimePeriodFlags(cCtx, &tp)
+			})
+			if tc.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+					t.Fatalf("error mismatch, got=%v, want=%s", err, tc.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(tp, tc.want) {
+				t.Errorf("time period mismatch,\n got=%+v,\nwant=%+v", tp, tc.want)
+			}
+		})
+	}
+}
+
+func TestUpdateTimePeriods(t *testing.T) {
+	startDate := time.Date(2030, 1, 2, 3, 4, 0, 0, time.Local)
+	tp1 := TimePeriod{
+		TimeperiodID:   "11",
+		Period:         time.Hour,
+		TimeperiodType: TimeperiodTypeOnetimeOnly,
+		StartDate:      startDate,
+	}
+	tp2 := TimePeriod{
+		TimeperiodID:   "12",
+		Period:         time.Hour,
+		TimeperiodType: TimeperiodTypeDaily,
+		StartTime:      2 * time.Hour,
+		Every:          1,
+	}
+	with := func(tp TimePeriod, f func(tp *TimePeriod)) TimePeriod {
+		f(&tp)
+		return tp
+	}
+
+	testCases := []struct {
+		name    string
+		tps     []TimePeriod
+		args    []string
+		want    []TimePeriod
+		wantErr string
+	}{
+		{
+			name: "noFlags",
+			tps:  []TimePeriod{tp1, tp2},
+			want: []TimePeriod{tp1, tp2},
+		},
+		{
+			name: "add",
+			tps:  []TimePeriod{tp1},
+			args: []string{"--add-timeperiod", "--period", "2h", "--timeperiod-type", "daily",
+				"--start-time", "02:00"},
+			want: []TimePeriod{tp1, {Period: 2 * time.Hour, TimeperiodType: TimeperiodTypeDaily,
+				StartTime: 2 * time.Hour}},
+		},
+		{
+			name:    "addWithoutPeriod",
+			tps:     []TimePeriod{tp1},
+			args:    []string{"--add-timeperiod", "--timeperiod-type", "daily"},
+			wantErr: `"--period" must be set with "--add-timeperiod"`,
+		},
+		{
+			name:    "addWithIndex",
+			tps:     []TimePeriod{tp1},
+			args:    []string{"--add-timeperiod", "--period", "1h", "--timeperiod-index", "1"},
+			wantErr: `"--add-timeperiod" cannot be used with`,
+		},
+		{
+			name: "removeByIndex",
+			tps:  []TimePeriod{tp1, tp2},
+			args: []string{"--remove-timeperiod", "--timeperiod-index", "2"},
+			want: []TimePeriod{tp1},
+		},
+		{
+			name: "removeByID",
+			tps:  []TimePeriod{tp1, tp2},
+			args: []string{"--remove-timeperiod", "--timeperiod-id", "11"},
+			want: []TimePeriod{tp2},
+		},
+		{
+			name:    "removeOnly",
+			tps:     []TimePeriod{tp1},
+			args:    []string{"--remove-timeperiod"},
+			wantErr: "cannot remove the only time period of maintenance",
+		},
+		{
+			name:    "removeWithPeriod",
+			tps:     []TimePeriod{tp1, tp2},
+			args:    []string{"--remove-timeperiod", "--timeperiod-index", "1", "--period", "2h"},
+			wantErr: `"--period" cannot be used with "--remove-timeperiod"`,
+		},
+		{
+			// The only time period can be updated without selecting it.
+			name: "updateOnly",
+			tps:  []TimePeriod{tp1},
+			args: []string{"--period", "2h"},
+			want: []TimePeriod{with(tp1, func(tp *TimePeriod) { tp.Period = 2 * time.Hour })},
+		},
+		{
+			name: "updateByIndex",
+			tps:  []TimePeriod{tp1, tp2},
+			args: []string{"--timeperiod-index", "2", "--start-time", "04:00"},
+			want: []TimePeriod{tp1, with(tp2, func(tp *TimePeriod) { tp.StartTime = 4 * time.Hour })},
+		},
+		{
+			name: "updateByID",
+			tps:  []TimePeriod{tp1, tp2},
+			args: []string{"--timeperiod-id", "11", "--start-date", "2030-02-01T00:00"},
+			want: []TimePeriod{with(tp1, func(tp *TimePeriod) {
+				tp.StartDate = time.Date(2030, 2, 1, 0, 0, 0, 0, time.Local)
+			}), tp2},
+		},
+		{
+			name:    "updateWithoutSelection",
+			tps:     []TimePeriod{tp1, tp2},
+			args:    []string{"--period", "2h"},
+			wantErr: `"--timeperiod-id" or "--timeperiod-index" must be set since maintenance has 2 time periods`,
+		},
+		{
+			name:    "indexTooLarge",
+			tps:     []TimePeriod{tp1, tp2},
+			args:    []string{"--timeperiod-index", "3", "--period", "2h"},
+			wantErr: `"--timeperiod-index" must be between 1 and 2, got 3`,
+		},
+		{
+			name:    "indexZero",
+			tps:     []TimePeriod{tp1, tp2},
+			args:    []string{"--remove-timeperiod", "--timeperiod-index", "0"},
+			wantErr: `"--timeperiod-index" must be between 1 and 2, got 0`,
+		},
+		{
+			name:    "unknownID",
+			tps:     []TimePeriod{tp1, tp2},
+			args:    []string{"--timeperiod-id", "99", "--period", "2h"},
+			wantErr: "time period not found in maintenance: 99",
+		},
+		{
+			name:    "idAndIndex",
+			tps:     []TimePeriod{tp1, tp2},
+			args:    []string{"--timeperiod-id", "11", "--timeperiod-index", "1", "--period", "2h"},
+			wantErr: `"--timeperiod-id" and "--timeperiod-index" cannot be used together`,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			m := &Maintenance{TimePeriods: append([]TimePeriod(nil), tc.tps...)}
+			err := runWithUpdateFlags(t, tc.args, func(cCtx *cli.Context) error {
+				return updateTimePeriods(cCtx, m)
+			})
+			if tc.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+					t.Fatalf("error mismatch, got=%v, want=%s", err, tc.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(m.TimePeriods, tc.want) {
+				t.Errorf("time periods mismatch,\n got=%+v,\nwant=%+v", m.TimePeriods, tc.want)
+			}
+		})
+	}
+}
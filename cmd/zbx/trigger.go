@@ -2,140 +2,57 @@ package main
 
 import (
 	"context"
-	"time"
 
-	"github.com/hnakamur/go-zabbix/internal/rpc"
 	"github.com/hnakamur/go-zabbix/internal/slicex"
+	"github.com/hnakamur/go-zabbix/trigger"
 )
 
-// https://www.zabbix.com/documentation/6.0/en/manual/api/reference/trigger/object
-type Trigger struct {
-	TriggerID   string
-	Description string
-	Expression  string
-	EventName   string
-	Comments    string
-	Error       string
-	LastChange  time.Time
-	State       string
-	Status      string
-	URL         string
-	Value       string
-	Groups      []HostGroup
-	Hosts       []Host
-	Items       []Item
-}
+type Trigger = trigger.Trigger
 
 func (c *myClient) GetTriggers(ctx context.Context, triggerIDs, hostNames, groupNames, descriptions []string) ([]Trigger, error) {
-	var hostIDs, groupIDs, itemIDs []string
-	if len(hostNames) > 0 {
-		hosts, err := c.inner.GetHostsByNamesFullMatch(ctx, hostNames)
-		if err != nil {
-			return nil, err
-		}
-		hostIDs = slicex.Map(hosts, func(h rpc.Host) string {
-			return h.HostID
-		})
-	}
-	if len(groupNames) > 0 {
-		groups, err := c.inner.GetNestedHostGroupsByAncestorNames(ctx, groupNames)
-		if err != nil {
-			return nil, err
-		}
-		groupIDs = slicex.Map(groups, func(h rpc.HostGroup) string {
-			return h.GroupID
-		})
-	}
-
-	triggers, err := c.inner.GetTriggers(ctx, triggerIDs, hostIDs, groupIDs, itemIDs, descriptions)
+	opts, err := c.triggerGetOptions(ctx, triggerIDs, hostNames, groupNames, descriptions)
 	if err != nil {
 		return nil, err
 	}
-	return slicex.FailableMap(triggers, fromPRCTrigger)
+	return trigger.Get(ctx, c.inner, opts)
 }
 
-func (c *myClient) SetTriggersStatus(ctx context.Context, triggerIDs []string, status rpc.TriggerStatus) ([]string, error) {
-	var updatedIDs []string
+func (c *myClient) SetTriggersStatus(ctx context.Context, triggerIDs []string, status trigger.Status) ([]string, error) {
+	return trigger.SetStatus(ctx, c.inner, triggerIDs, status)
+}
 
-	for _, triggerID := range triggerIDs {
-		ids, err := c.inner.SetTriggersStatus(ctx, triggerID, status)
-		updatedIDs = append(updatedIDs, ids...)
-		if err != nil {
-			return updatedIDs, err
-		}
+func (c *myClient) GetTriggerIDs(ctx context.Context, triggerIDs, hostNames, groupNames, descriptions []string) ([]string, error) {
+	opts, err := c.triggerGetOptions(ctx, triggerIDs, hostNames, groupNames, descriptions)
+	if err != nil {
+		return nil, err
 	}
-	return updatedIDs, nil
+	return trigger.GetIDs(ctx, c.inner, opts)
 }
 
-func (c *myClient) GetTriggerIDs(ctx context.Context, triggerIDs, hostNames, groupNames, descriptions []string) ([]string, error) {
-	var hostIDs, groupIDs, itemIDs []string
+func (c *myClient) triggerGetOptions(ctx context.Context, triggerIDs, hostNames, groupNames, descriptions []string) (trigger.GetOptions, error) {
+	opts := trigger.GetOptions{
+		TriggerIDs:   triggerIDs,
+		Descriptions: descriptions,
+	}
 	if len(hostNames) > 0 {
-		hosts, err := c.inner.GetHostsByNamesFullMatch(ctx, hostNames)
+		hosts, err := c.GetHostsByNamesFullMatch(ctx, hostNames)
 		if err != nil {
-			return nil, err
+			return trigger.GetOptions{}, err
 		}
-		hostIDs = slicex.Map(hosts, func(h rpc.Host) string {
+		opts.HostIDs = slicex.Map(hosts, func(h Host) string {
 			return h.HostID
 		})
 	}
 	if len(groupNames) > 0 {
-		groups, err := c.inner.GetNestedHostGroupsByAncestorNames(ctx, groupNames)
+		groups, err := c.GetNestedHostGroupsByAncestorNames(ctx, groupNames)
 		if err != nil {
-			return nil, err
+			return trigger.GetOptions{}, err
 		}
-		groupIDs = slicex.Map(groups, func(h rpc.HostGroup) string {
-			return h.GroupID
+		opts.GroupIDs = slicex.Map(groups, func(g HostGroup) string {
+			return g.GroupID
 		})
 	}
-
-	triggerIDs, err := c.inner.GetTriggerIDs(ctx, triggerIDs, hostIDs, groupIDs, itemIDs, descriptions)
-	if err != nil {
-		return nil, err
-	}
-	return triggerIDs, nil
-}
-
-func fromPRCTrigger(t rpc.Trigger) (Trigger, error) {
-	lastChange, err := ParseTimestamp(t.LastChange)
-	if err != nil {
-		return Trigger{}, err
-	}
-
-	hosts, err := slicex.FailableMap(t.Hosts, fromRPCHost)
-	if err != nil {
-		return Trigger{}, err
-	}
-
-	return Trigger{
-		TriggerID:   t.TriggerID,
-		Description: t.Description,
-		Expression:  t.Expression,
-		EventName:   t.EventName,
-		Comments:    t.Comments,
-		Error:       t.Error,
-		LastChange:  time.Time(lastChange),
-		State:       t.State,
-		Status:      t.Status,
-		URL:         t.URL,
-		Value:       t.Value,
-		Groups:      t.Groups,
-		Hosts:       hosts,
-		Items:       t.Items,
-	}, nil
-}
-
-func toRPCTrigger(t Trigger) (rpc.Trigger, error) {
-	return rpc.Trigger{
-		TriggerID:   t.TriggerID,
-		Description: t.Description,
-		Expression:  t.Expression,
-		EventName:   t.EventName,
-		Comments:    t.Comments,
-		Error:       t.Error,
-		Status:      t.Status,
-		URL:         t.URL,
-		// Keep empty values for readonly properties
-	}, nil
+	return opts, nil
 }
 
 type displayTrigger struct {
@@ -163,11 +80,11 @@ func toDisplayTrigger(t Trigger) displayTrigger {
 		EventName:   t.EventName,
 		Comments:    t.Comments,
 		Error:       t.Error,
-		LastChange:  displayTimestamp(time.Time(t.LastChange)),
-		State:       t.State,
-		Status:      t.Status,
+		LastChange:  displayTimestamp(t.LastChange),
+		State:       string(t.State),
+		Status:      string(t.Status),
 		URL:         t.URL,
-		Value:       t.Value,
+		Value:       string(t.Value),
 		Groups:      t.Groups,
 		Hosts:       slicex.Map(t.Hosts, toDisplayHost),
 		Items:       t.Items,
@@ -0,0 +1,80 @@
+// Package credentials provides implementations of zabbix.CredentialsProvider
+// which read secrets from a file, the output of a command, or an encrypted
+// credential store, so that secrets need not be passed in environment
+// variables or command line arguments.
+package credentials
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+
+	"github.com/hnakamur/go-zabbix"
+)
+
+// Static returns a provider which always returns creds.
+func Static(creds zabbix.Credentials) zabbix.CredentialsProvider {
+	return zabbix.CredentialsFunc(func(ctx context.Context) (zabbix.Credentials, error) {
+		return creds, nil
+	})
+}
+
+// TokenFile returns a provider which reads an API token from the file at path.
+// Leading and trailing white spaces are trimmed. The file is read every time
+// credentials are requested, so that a rotated token is used.
+func TokenFile(path string) zabbix.CredentialsProvider {
+	return zabbix.CredentialsFunc(func(ctx context.Context) (zabbix.Credentials, error) {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return zabbix.Credentials{}, fmt.Errorf("read token file: %w", err)
+		}
+		token := strings.TrimSpace(string(data))
+		if token == "" {
+			return zabbix.Credentials{}, fmt.Errorf("token file is empty: %s", path)
+		}
+		return zabbix.Credentials{APIToken: token}, nil
+	})
+}
+
+// PasswordCommand returns a provider which runs command with the shell and
+// uses the first line of its standard output as the password for username,
+// like "pass show zabbix/admin". The command is run every time credentials
+// are requested.
+func PasswordCommand(username, command string) zabbix.CredentialsProvider {
+	return zabbix.CredentialsFunc(func(ctx context.Context) (zabbix.Credentials, error) {
+		password, err := runCommand(ctx, command)
+		if err != nil {
+			return zabbix.Credentials{}, err
+		}
+		return zabbix.Credentials{Username: username, Password: password}, nil
+	})
+}
+
+func runCommand(ctx context.Context, command string) (string, error) {
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.CommandContext(ctx, "cmd", "/C", command)
+	} else {
+		cmd = exec.CommandContext(ctx, "sh", "-c", command)
+	}
+	out, err := cmd.Output()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && len(exitErr.Stderr) > 0 {
+			return "", fmt.Errorf("run password command: %w: %s", err,
+				bytes.TrimSpace(exitErr.Stderr))
+		}
+		return "", fmt.Errorf("run password command: %w", err)
+	}
+	line, _, _ := bufio.NewReader(bytes.NewReader(out)).ReadLine()
+	if len(line) == 0 {
+		return "", errors.New("password command printed an empty password")
+	}
+	return string(line), nil
+}
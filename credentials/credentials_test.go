@@ -0,0 +1,115 @@
+package credentials
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/hnakamur/go-zabbix"
+)
+
+func TestTokenFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "token")
+	p := TokenFile(path)
+	ctx := context.Background()
+
+	if _, err := p.Credentials(ctx); err == nil {
+		t.Error("want error for missing file, got nil")
+	}
+
+	for _, token := range []string{"token1", "token2"} {
+		if err := os.WriteFile(path, []byte(token+"\n"), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		creds, err := p.Credentials(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got, want := creds, (zabbix.Credentials{APIToken: token}); got != want {
+			t.Errorf("credentials mismatch, got=%+v, want=%+v", got, want)
+		}
+	}
+}
+
+func TestPasswordCommand(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("commands in this test need sh")
+	}
+
+	testCases := []struct {
+		command string
+		want    zabbix.Credentials
+		wantErr bool
+	}{
+		{command: `printf 'secret\nurl: https://example.com\n'`,
+			want: zabbix.Credentials{Username: "Admin", Password: "secret"}},
+		{command: `echo failed >&2; exit 1`, wantErr: true},
+		{command: `true`, wantErr: true},
+	}
+	for _, c := range testCases {
+		creds, err := PasswordCommand("Admin", c.command).Credentials(context.Background())
+		if gotErr := err != nil; gotErr != c.wantErr {
+			t.Errorf("error mismatch for command %q, got=%v, wantErr=%v", c.command, err, c.wantErr)
+			continue
+		}
+		if got, want := creds, c.want; got != want {
+			t.Errorf("credentials mismatch for command %q, got=%+v, want=%+v", c.command, got, want)
+		}
+	}
+}
+
+func TestStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "zbx", "credentials.json")
+	passphrase := func() ([]byte, error) { return []byte("passphrase1"), nil }
+	s := NewStore(path, passphrase)
+
+	if _, err := s.Get("https://zabbix1.example.com/"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("error mismatch for empty store, got=%v, want=%v", err, ErrNotFound)
+	}
+
+	want1 := zabbix.Credentials{APIToken: "token1"}
+	want2 := zabbix.Credentials{Username: "Admin", Password: "zabbix"}
+	if err := s.Set("https://zabbix1.example.com/", want1); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Set("https://zabbix2.example.com/", want2); err != nil {
+		t.Fatal(err)
+	}
+	fi, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if runtime.GOOS != "windows" {
+		if got, want := fi.Mode().Perm(), os.FileMode(0o600); got != want {
+			t.Errorf("file mode mismatch, got=%v, want=%v", got, want)
+		}
+	}
+
+	// Read with another Store to make sure the file is decrypted.
+	s2 := NewStore(path, passphrase)
+	got, err := s2.Provider("https://zabbix2.example.com/").Credentials(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != want2 {
+		t.Errorf("credentials mismatch, got=%+v, want=%+v", got, want2)
+	}
+
+	if err := s2.Delete("https://zabbix2.example.com/"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Get("https://zabbix2.example.com/"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("error mismatch after delete, got=%v, want=%v", err, ErrNotFound)
+	}
+	if got, err := s.Get("https://zabbix1.example.com/"); err != nil || got != want1 {
+		t.Errorf("credentials mismatch after delete, got=%+v, err=%v, want=%+v", got, err, want1)
+	}
+
+	wrong := NewStore(path, func() ([]byte, error) { return []byte("wrong"), nil })
+	if _, err := wrong.Get("https://zabbix1.example.com/"); !errors.Is(err, ErrWrongPassphrase) {
+		t.Errorf("error mismatch for wrong passphrase, got=%v, want=%v", err, ErrWrongPassphrase)
+	}
+}
@@ -0,0 +1,228 @@
+package credentials
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"sync"
+
+	"golang.org/x/crypto/scrypt"
+
+	"github.com/hnakamur/go-zabbix"
+	"github.com/hnakamur/go-zabbix/internal/fileutil"
+)
+
+// ErrNotFound is returned by Store.Get when no credentials are stored for
+// the key.
+var ErrNotFound = errors.New("credentials: not found in the store")
+
+// ErrWrongPassphrase is returned when the store cannot be decrypted with the
+// passphrase.
+var ErrWrongPassphrase = errors.New("credentials: wrong passphrase or corrupted store")
+
+const storeVersion = 1
+
+// Parameters of scrypt recommended for interactive logins.
+// https://pkg.go.dev/golang.org/x/crypto/scrypt#Key
+const (
+	scryptN = 1 << 15
+	scryptR = 8
+	scryptP = 1
+
+	keySize  = 32
+	saltSize = 16
+)
+
+// Store is a file which keeps credentials for Zabbix servers, encrypted with
+// AES-256-GCM and a key derived from a passphrase with scrypt.
+// Credentials are identified by keys, such as the URL of the server.
+type Store struct {
+	path          string
+	getPassphrase func() ([]byte, error)
+
+	mu         sync.Mutex
+	passphrase []byte
+}
+
+// storeFile is the content of the store file.
+type storeFile struct {
+	Version    int    `json:"version"`
+	Salt       []byte `json:"salt"`
+	N          int    `json:"n"`
+	R          int    `json:"r"`
+	P          int    `json:"p"`
+	Nonce      []byte `json:"nonce"`
+	Ciphertext []byte `json:"ciphertext"`
+}
+
+// storeEntry is an element of the decrypted content of the store file.
+type storeEntry struct {
+	Username string `json:"username,omitempty"`
+	Password string `json:"password,omitempty"`
+	APIToken string `json:"api_token,omitempty"`
+}
+
+// NewStore returns a Store for the file at path. The file need not exist until
+// Set is called. getPassphrase is called when the store is accessed for the
+// first time, and the passphrase is kept in the Store after that.
+func NewStore(path string, getPassphrase func() ([]byte, error)) *Store {
+	return &Store{path: path, getPassphrase: getPassphrase}
+}
+
+// Get returns the credentials for key. It returns ErrNotFound if no
+// credentials are stored for key.
+func (s *Store) Get(key string) (zabbix.Credentials, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entries, err := s.load()
+	if err != nil {
+		return zabbix.Credentials{}, err
+	}
+	e, ok := entries[key]
+	if !ok {
+		return zabbix.Credentials{}, ErrNotFound
+	}
+	return zabbix.Credentials{
+		APIToken: e.APIToken,
+		Username: e.Username,
+		Password: e.Password,
+	}, nil
+}
+
+// Set stores the credentials for key, replacing existing ones.
+// The store file is created with the permission 0600 if it does not exist.
+func (s *Store) Set(key string, creds zabbix.Credentials) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entries, err := s.load()
+	if err != nil {
+		return err
+	}
+	entries[key] = storeEntry{
+		Username: creds.Username,
+		Password: creds.Password,
+		APIToken: creds.APIToken,
+	}
+	return s.save(entries)
+}
+
+// Delete removes the credentials for key. It returns ErrNotFound if no
+// credentials are stored for key.
+func (s *Store) Delete(key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entries, err := s.load()
+	if err != nil {
+		return err
+	}
+	if _, ok := entries[key]; !ok {
+		return ErrNotFound
+	}
+	delete(entries, key)
+	return s.save(entries)
+}
+
+// Provider returns a provider which returns the credentials for key.
+func (s *Store) Provider(key string) zabbix.CredentialsProvider {
+	return zabbix.CredentialsFunc(func(ctx context.Context) (zabbix.Credentials, error) {
+		return s.Get(key)
+	})
+}
+
+// load reads and decrypts the store file. It returns an empty map if the file
+// does not exist.
+func (s *Store) load() (map[string]storeEntry, error) {
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return make(map[string]storeEntry), nil
+		}
+		return nil, err
+	}
+	var f storeFile
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("credentials: parse store %s: %w", s.path, err)
+	}
+	if f.Version != storeVersion {
+		return nil, fmt.Errorf("credentials: unsupported store version: %d", f.Version)
+	}
+
+	aead, err := s.newAEAD(f.Salt, f.N, f.R, f.P)
+	if err != nil {
+		return nil, err
+	}
+	plaintext, err := aead.Open(nil, f.Nonce, f.Ciphertext, nil)
+	if err != nil {
+		// Ask the passphrase again next time.
+		s.passphrase = nil
+		return nil, ErrWrongPassphrase
+	}
+	entries := make(map[string]storeEntry)
+	if err := json.Unmarshal(plaintext, &entries); err != nil {
+		return nil, ErrWrongPassphrase
+	}
+	return entries, nil
+}
+
+// save encrypts entries with a new salt and writes them to the store file.
+func (s *Store) save(entries map[string]storeEntry) error {
+	plaintext, err := json.Marshal(entries)
+	if err != nil {
+		return err
+	}
+	f := storeFile{
+		Version: storeVersion,
+		Salt:    make([]byte, saltSize),
+		N:       scryptN,
+		R:       scryptR,
+		P:       scryptP,
+	}
+	if _, err := rand.Read(f.Salt); err != nil {
+		return err
+	}
+	aead, err := s.newAEAD(f.Salt, f.N, f.R, f.P)
+	if err != nil {
+		return err
+	}
+	f.Nonce = make([]byte, aead.NonceSize())
+	if _, err := rand.Read(f.Nonce); err != nil {
+		return err
+	}
+	f.Ciphertext = aead.Seal(nil, f.Nonce, plaintext, nil)
+	data, err := json.MarshalIndent(f, "", "  ")
+	if err != nil {
+		return err
+	}
+	return fileutil.WriteFileAtomic(s.path, data)
+}
+
+func (s *Store) newAEAD(salt []byte, n, r, p int) (cipher.AEAD, error) {
+	if s.passphrase == nil {
+		passphrase, err := s.getPassphrase()
+		if err != nil {
+			return nil, err
+		}
+		if len(passphrase) == 0 {
+			return nil, errors.New("credentials: empty passphrase")
+		}
+		s.passphrase = passphrase
+	}
+	key, err := scrypt.Key(s.passphrase, salt, n, r, p, keySize)
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
@@ -4,4 +4,7 @@
 //
 // Users can refer to https://www.zabbix.com/documentation/6.0/en/manual/api
 // and create functions for their use cases.
+//
+// Typed wrappers for some APIs are provided in the subpackages host,
+// hostgroup, item, maintenance and trigger.
 package zabbix
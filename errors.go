@@ -0,0 +1,272 @@
+package zabbix
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+)
+
+// CallError is the error type returned by Client.Call method.
+// The concret type of the Err field is APIError or other error.
+type CallError struct {
+	ID     uint64 `json:"id"`
+	Method string `json:"method"`
+	Params any    `json:"params"`
+	Err    error  `json:"error"`
+
+	// StatusCode and Body are set when the response is received but it is
+	// not JSON, for example an error page of a reverse proxy. In that case,
+	// Err is an HTTPError or an error of decoding JSON.
+	// StatusCode is the HTTP status code of the response.
+	StatusCode int `json:"status_code,omitempty"`
+	// Body is the beginning of the response body.
+	Body string `json:"body,omitempty"`
+}
+
+var _ error = (*CallError)(nil)
+
+func (e *CallError) Error() string {
+	type callError CallError
+	ce := callError(*e)
+	v := struct {
+		*callError
+		Err any `json:"error"`
+	}{
+		callError: &ce,
+		Err:       e.Err,
+	}
+	// Errors other than APIError do not have exported fields in most cases,
+	// so their messages are used instead.
+	if _, ok := e.Err.(*APIError); !ok && e.Err != nil {
+		v.Err = e.Err.Error()
+	}
+	// The message of HTTPError already contains the status code and the body.
+	var httpErr *HTTPError
+	if errors.As(e.Err, &httpErr) {
+		ce.StatusCode = 0
+		ce.Body = ""
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		panic(err)
+	}
+	return string(data)
+}
+
+func (e *CallError) Unwrap() error {
+	return e.Err
+}
+
+type ErrorCode int
+
+const (
+	// ErrorCodeNone represents the error which is not an error object from
+	// Zabbix JSON-RPC API but some other error occurred in the client side.
+	ErrorCodeNone ErrorCode = 0
+
+	ErrorCodeParse          ErrorCode = -32700
+	ErrorCodeInvalidRequest ErrorCode = -32600
+	ErrorCodeMethodNotFound ErrorCode = -32601
+	ErrorCodeInvalidParams  ErrorCode = -32602
+	ErrorCodeInternal       ErrorCode = -32603
+	ErrorCodeApplication    ErrorCode = -32500
+	ErrorCodeSystem         ErrorCode = -32400
+	ErrorCodeTransport      ErrorCode = -32300
+)
+
+// GetErrorCode returns the Code field if err or a unwrapped error is APIError
+// or ErrorCodeNone otherwise.
+func GetErrorCode(err error) ErrorCode {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.Code
+	}
+	return ErrorCodeNone
+}
+
+// APIError is an error object in responses from Zabbix JSON-RPC API.
+// https://www.zabbix.com/documentation/current/en/manual/api#error-handling
+type APIError struct {
+	Code    ErrorCode `json:"code"`
+	Message string    `json:"message"`
+	Data    string    `json:"data"`
+}
+
+var _ error = (*APIError)(nil)
+
+func (e *APIError) Error() string {
+	data, err := json.Marshal(e)
+	if err != nil {
+		panic(err)
+	}
+	return string(data)
+}
+
+// Sentinel errors for classifying errors from Zabbix API.
+// An APIError matches them with errors.Is according to its Code and Data.
+var (
+	// ErrNotFoundOrNoPermission means the referred object does not exist or
+	// the user has no permission to it. Zabbix does not distinguish them.
+	ErrNotFoundOrNoPermission = errors.New("zabbix: object not found or no permission")
+	// ErrSessionExpired means the session is terminated, the session ID
+	// or the API token is invalid, or it is not specified.
+	ErrSessionExpired = errors.New("zabbix: session expired or not authorised")
+	// ErrAlreadyExists means an object with the same name already exists.
+	ErrAlreadyExists = errors.New("zabbix: object already exists")
+	// ErrInvalidParams means the params of the request are invalid.
+	ErrInvalidParams = errors.New("zabbix: invalid params")
+)
+
+// Is returns whether e is classified as target, which is one of sentinel
+// errors like ErrSessionExpired.
+func (e *APIError) Is(target error) bool {
+	switch target {
+	case ErrNotFoundOrNoPermission:
+		return strings.Contains(e.Data, "No permissions to referred object or it does not exist") ||
+			strings.Contains(e.Data, "does not exist or you have no access")
+	case ErrSessionExpired:
+		return strings.Contains(e.Data, "Session terminated") ||
+			strings.Contains(e.Data, "Not authorised") ||
+			strings.Contains(e.Data, "API token expired")
+	case ErrAlreadyExists:
+		return strings.Contains(e.Data, "already exists")
+	case ErrInvalidParams:
+		return e.Code == ErrorCodeInvalidParams
+	}
+	return false
+}
+
+// IsNotFoundOrNoPermission returns whether err is or wraps an APIError
+// returned when the referred object does not exist or the user has no
+// permission to it.
+func IsNotFoundOrNoPermission(err error) bool {
+	return errors.Is(err, ErrNotFoundOrNoPermission)
+}
+
+// IsSessionExpired returns whether err is or wraps an APIError returned when
+// the session is terminated or the session ID is invalid.
+func IsSessionExpired(err error) bool {
+	return errors.Is(err, ErrSessionExpired)
+}
+
+// IsAlreadyExists returns whether err is or wraps an APIError returned when
+// an object with the same name already exists.
+func IsAlreadyExists(err error) bool {
+	return errors.Is(err, ErrAlreadyExists)
+}
+
+// IsInvalidParams returns whether err is or wraps an APIError whose code is
+// ErrorCodeInvalidParams.
+func IsInvalidParams(err error) bool {
+	return errors.Is(err, ErrInvalidParams)
+}
+
+// HTTPError is the error returned when the HTTP status code of the response
+// is not 2xx or the content type is not JSON, for example a login page of an
+// authentication proxy or an error page of a reverse proxy.
+// It is wrapped in CallError and distinguished from APIError with errors.As.
+type HTTPError struct {
+	StatusCode int
+	Header     http.Header
+	// Body is the beginning of the response body.
+	Body string
+}
+
+var _ error = (*HTTPError)(nil)
+
+func (e *HTTPError) Error() string {
+	return fmt.Sprintf("unexpected HTTP response: status=%d, content_type=%q, body=%q",
+		e.StatusCode, e.Header.Get("Content-Type"), e.Body)
+}
+
+// checkHTTPResponse returns an HTTPError if the status code of res is not 2xx
+// or the content type is not JSON. body is the reader of the response body.
+func checkHTTPResponse(res *http.Response, body io.Reader) *HTTPError {
+	if res.StatusCode/100 == 2 && isJSONContentType(res.Header.Get("Content-Type")) {
+		return nil
+	}
+	head, _ := io.ReadAll(io.LimitReader(body, bodySnippetSize))
+	return &HTTPError{
+		StatusCode: res.StatusCode,
+		Header:     res.Header,
+		Body:       string(head),
+	}
+}
+
+// isJSONContentType returns whether contentType is a JSON media type.
+// An empty content type is also accepted since some servers omit it.
+func isJSONContentType(contentType string) bool {
+	if contentType == "" {
+		return true
+	}
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	return mediaType == "application/json" || mediaType == "application/json-rpc" ||
+		strings.HasSuffix(mediaType, "+json")
+}
+
+// bodySnippetSize is the maximum size of the response body kept for
+// CallError and HTTPError.
+const bodySnippetSize = 512
+
+// newCallError returns a CallError wrapping err for req. If the response of
+// req is not JSON, the status code and the beginning of the body are also
+// set. Secrets in the params are replaced as in logs, since errors are often
+// logged.
+func newCallError(req *rpcRequest, err error) *CallError {
+	e := &CallError{
+		ID:     req.ID,
+		Method: req.Method,
+		Params: req.redacted().Params,
+		Err:    err,
+	}
+	var httpErr *HTTPError
+	if errors.As(err, &httpErr) {
+		e.StatusCode = httpErr.StatusCode
+		e.Body = httpErr.Body
+	} else if req.statusCode != 0 && isNotJSONError(err) {
+		e.StatusCode = req.statusCode
+		body := req.respBodyBytes
+		if len(body) > bodySnippetSize {
+			body = body[:bodySnippetSize]
+		}
+		e.Body = string(body)
+	}
+	return e
+}
+
+// isNotJSONError returns whether err is returned when decoding a response
+// body which is not JSON.
+func isNotJSONError(err error) bool {
+	var syntaxErr *json.SyntaxError
+	return errors.As(err, &syntaxErr) || errors.Is(err, io.EOF) ||
+		errors.Is(err, io.ErrUnexpectedEOF)
+}
+
+// headRecorder is an io.Reader which keeps the first bodySnippetSize bytes
+// read from r.
+type headRecorder struct {
+	r    io.Reader
+	head []byte
+}
+
+func (h *headRecorder) Read(p []byte) (int, error) {
+	n, err := h.r.Read(p)
+	if room := bodySnippetSize - len(h.head); room > 0 {
+		h.head = append(h.head, p[:min(n, room)]...)
+	}
+	return n, err
+}
+
+// fill reads r until bodySnippetSize bytes are kept or r reaches EOF.
+func (h *headRecorder) fill() {
+	if room := bodySnippetSize - len(h.head); room > 0 {
+		io.CopyN(io.Discard, h, int64(room))
+	}
+}
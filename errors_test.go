@@ -0,0 +1,164 @@
+package zabbix
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestAPIErrorIs(t *testing.T) {
+	testCases := []struct {
+		name   string
+		err    *APIError
+		target error
+	}{
+		{name: "notFound", target: ErrNotFoundOrNoPermission, err: &APIError{
+			Code: ErrorCodeApplication, Message: "Application error.",
+			Data: "No permissions to referred object or it does not exist!"}},
+		{name: "sessionTerminated", target: ErrSessionExpired, err: &APIError{
+			Code: ErrorCodeInvalidParams, Message: "Invalid params.",
+			Data: "Session terminated, re-login, please."}},
+		{name: "notAuthorised", target: ErrSessionExpired, err: &APIError{
+			Code: ErrorCodeInvalidParams, Message: "Invalid params.",
+			Data: "Not authorised."}},
+		{name: "alreadyExists", target: ErrAlreadyExists, err: &APIError{
+			Code: ErrorCodeInvalidParams, Message: "Invalid params.",
+			Data: `Maintenance "x" already exists.`}},
+		{name: "invalidParams", target: ErrInvalidParams, err: &APIError{
+			Code: ErrorCodeInvalidParams, Message: "Invalid params.",
+			Data: `Invalid parameter "/1": the parameter "name" is missing.`}},
+	}
+	targets := []error{ErrNotFoundOrNoPermission, ErrSessionExpired,
+		ErrAlreadyExists, ErrInvalidParams}
+	for _, c := range testCases {
+		t.Run(c.name, func(t *testing.T) {
+			err := fmt.Errorf("wrapped: %w", &CallError{Method: "host.get", Err: c.err})
+			for _, target := range targets {
+				want := target == c.target ||
+					(target == ErrInvalidParams && c.err.Code == ErrorCodeInvalidParams)
+				if got := errors.Is(err, target); got != want {
+					t.Errorf("errors.Is result mismatch for %v, got=%v, want=%v", target, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestCallErrorNotJSON(t *testing.T) {
+	testCases := []struct {
+		name          string
+		statusCode    int
+		contentType   string
+		body          string
+		wantHTTPError bool
+	}{
+		{name: "forbidden", statusCode: http.StatusForbidden, contentType: "text/html",
+			body:          "<html><body>403 Forbidden" + strings.Repeat(" ", 1000) + "</body></html>",
+			wantHTTPError: true},
+		{name: "loginPage", statusCode: http.StatusOK, contentType: "text/html; charset=utf-8",
+			body: "<html><body>Login</body></html>", wantHTTPError: true},
+		{name: "brokenJSON", statusCode: http.StatusOK, contentType: "application/json",
+			body: `{"jsonrpc":"2.0","result":`, wantHTTPError: false},
+	}
+	for _, c := range testCases {
+		t.Run(c.name, func(t *testing.T) {
+			s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", c.contentType)
+				w.WriteHeader(c.statusCode)
+				w.Write([]byte(c.body))
+			}))
+			defer s.Close()
+
+			client, err := NewClient(s.URL)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var version string
+			err = client.Call(context.Background(), apiVersionMethod, []string{}, &version)
+			var callErr *CallError
+			if !errors.As(err, &callErr) {
+				t.Fatalf("want CallError, got=%v", err)
+			}
+			if got, want := callErr.StatusCode, c.statusCode; got != want {
+				t.Errorf("status code mismatch, got=%d, want=%d", got, want)
+			}
+			wantBody := c.body
+			if len(wantBody) > bodySnippetSize {
+				wantBody = wantBody[:bodySnippetSize]
+			}
+			if got := callErr.Body; got != wantBody {
+				t.Errorf("body mismatch, got=%q, want=%q", got, wantBody)
+			}
+
+			var httpErr *HTTPError
+			if got := errors.As(err, &httpErr); got != c.wantHTTPError {
+				t.Fatalf("HTTPError mismatch, got=%v, want=%v, err=%v", got, c.wantHTTPError, err)
+			}
+			if httpErr != nil {
+				if got, want := httpErr.StatusCode, c.statusCode; got != want {
+					t.Errorf("HTTPError status code mismatch, got=%d, want=%d", got, want)
+				}
+				if got, want := httpErr.Header.Get("Content-Type"), c.contentType; got != want {
+					t.Errorf("HTTPError content type mismatch, got=%s, want=%s", got, want)
+				}
+			}
+			if GetErrorCode(err) != ErrorCodeNone {
+				t.Errorf("HTTP error must not be an APIError, got=%v", err)
+			}
+		})
+	}
+}
+
+func TestCallErrorRedactsSecrets(t *testing.T) {
+	var received []testRequest
+	s := newTestServer(t, "6.4.0", &received, func(req testRequest) any {
+		return &APIError{Code: ErrorCodeInvalidParams, Message: "Invalid params.",
+			Data: "Not authorized."}
+	})
+	client, err := NewClient(s.URL, WithAPIToken("secret-token"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	testCases := []struct {
+		name   string
+		call   func() error
+		secret string
+	}{
+		{
+			name: "login",
+			call: func() error {
+				return client.Login(ctx, "Admin", "secret-password")
+			},
+			secret: "secret-password",
+		},
+		{
+			name: "checkAuthentication",
+			call: func() error {
+				_, err := client.CheckAuthentication(ctx)
+				return err
+			},
+			secret: "secret-token",
+		},
+	}
+	for _, c := range testCases {
+		t.Run(c.name, func(t *testing.T) {
+			err := c.call()
+			var callErr *CallError
+			if !errors.As(err, &callErr) {
+				t.Fatalf("want CallError, got=%v", err)
+			}
+			if strings.Contains(err.Error(), c.secret) {
+				t.Errorf("error must not contain secret, got=%v", err)
+			}
+			if !strings.Contains(err.Error(), hiddenSecretForLog) {
+				t.Errorf("error must contain %q, got=%v", hiddenSecretForLog, err)
+			}
+		})
+	}
+}
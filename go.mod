@@ -1,16 +1,18 @@
 module github.com/hnakamur/go-zabbix
 
-go 1.20
+go 1.21
 
 require (
 	github.com/urfave/cli/v2 v2.25.7
+	golang.org/x/crypto v0.31.0
 	golang.org/x/exp v0.0.0-20230522175609-2e198f4a06a1
-	golang.org/x/term v0.9.0
+	golang.org/x/term v0.27.0
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
 	github.com/cpuguy83/go-md2man/v2 v2.0.2 // indirect
 	github.com/russross/blackfriday/v2 v2.1.0 // indirect
 	github.com/xrash/smetrics v0.0.0-20201216005158-039620a65673 // indirect
-	golang.org/x/sys v0.9.0 // indirect
+	golang.org/x/sys v0.28.0 // indirect
 )
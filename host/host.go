@@ -0,0 +1,165 @@
+// Package host provides functions for Zabbix host API.
+// https://www.zabbix.com/documentation/6.0/en/manual/api/reference/host
+package host
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"golang.org/x/exp/slices"
+
+	"github.com/hnakamur/go-zabbix"
+	"github.com/hnakamur/go-zabbix/internal/field"
+)
+
+// https://www.zabbix.com/documentation/6.0/en/manual/api/reference/host/object
+type Host struct {
+	HostID            string
+	Name              string
+	MaintenanceFrom   time.Time
+	MaintenanceStatus MaintenanceStatus
+	MaintenanceType   MaintenanceType
+	MaintenanceID     string
+}
+
+type MaintenanceStatus string
+
+const (
+	MaintenanceStatusNoMaintenance MaintenanceStatus = "0"
+	MaintenanceStatusInEffect      MaintenanceStatus = "1"
+)
+
+// MaintenanceType is the type of maintenance. It is defined in this package
+// instead of the maintenance package since Host has it.
+type MaintenanceType string
+
+const (
+	MaintenanceTypeWithData MaintenanceType = "0"
+	MaintenanceTypeNoData   MaintenanceType = "1"
+)
+
+type rawHost struct {
+	HostID            string `json:"hostid"`
+	Name              string `json:"name,omitempty"`
+	MaintenanceFrom   string `json:"maintenance_from,omitempty"`
+	MaintenanceStatus string `json:"maintenance_status,omitempty"`
+	MaintenanceType   string `json:"maintenance_type,omitempty"`
+	MaintenanceID     string `json:"maintenanceid,omitempty"`
+}
+
+// OutputFields is the properties of hosts returned by functions in this
+// package. It can be used for "selectHosts" parameter of other APIs.
+var OutputFields = []string{"hostid", "name", "maintenance_from",
+	"maintenance_status", "maintenance_type", "maintenanceid"}
+
+// MarshalJSON encodes only writable properties.
+func (h Host) MarshalJSON() ([]byte, error) {
+	return json.Marshal(rawHost{
+		HostID: h.HostID,
+		Name:   h.Name,
+		// Keep empty values for readonly properties
+	})
+}
+
+func (h *Host) UnmarshalJSON(data []byte) error {
+	var r rawHost
+	if err := json.Unmarshal(data, &r); err != nil {
+		return err
+	}
+	maintenanceFrom, err := field.ParseTimestamp(r.MaintenanceFrom)
+	if err != nil {
+		return err
+	}
+
+	*h = Host{
+		HostID:            r.HostID,
+		Name:              r.Name,
+		MaintenanceFrom:   time.Time(maintenanceFrom),
+		MaintenanceStatus: MaintenanceStatus(r.MaintenanceStatus),
+		MaintenanceType:   MaintenanceType(r.MaintenanceType),
+		MaintenanceID:     r.MaintenanceID,
+	}
+	return nil
+}
+
+// GetByNamesFullMatch returns hosts whose names are one of names.
+// It returns an error if any of names is not found.
+func GetByNamesFullMatch(ctx context.Context, c *zabbix.Client,
+	names []string) ([]Host, error) {
+	type Names struct {
+		Name []string `json:"name"`
+	}
+
+	params := struct {
+		Output any `json:"output"`
+		Filter any `json:"filter"`
+	}{
+		Output: OutputFields,
+		Filter: Names{
+			Name: names,
+		},
+	}
+	hosts, err := zabbix.CallTyped[[]Host](ctx, c, "host.get", params)
+	if err != nil {
+		return nil, err
+	}
+
+	var notFoundNames []string
+	for _, name := range names {
+		if !slices.ContainsFunc(hosts, func(host Host) bool {
+			return host.Name == name
+		}) {
+			notFoundNames = append(notFoundNames, name)
+		}
+	}
+	if len(notFoundNames) > 0 {
+		return nil, fmt.Errorf("hosts not found: %s", strings.Join(notFoundNames, ", "))
+	}
+	return hosts, nil
+}
+
+// GetByHostIDs returns hosts whose IDs are one of hostIDs.
+// It returns an error if any of hostIDs is not found.
+func GetByHostIDs(ctx context.Context, c *zabbix.Client,
+	hostIDs []string) ([]Host, error) {
+	params := struct {
+		Output  any `json:"output"`
+		HostIDs any `json:"hostids"`
+	}{
+		Output:  OutputFields,
+		HostIDs: hostIDs,
+	}
+	hosts, err := zabbix.CallTyped[[]Host](ctx, c, "host.get", params)
+	if err != nil {
+		return nil, err
+	}
+
+	var notFoundHostIDs []string
+	for _, hostID := range hostIDs {
+		if !slices.ContainsFunc(hosts, func(host Host) bool {
+			return host.HostID == hostID
+		}) {
+			notFoundHostIDs = append(notFoundHostIDs, hostID)
+		}
+	}
+	if len(notFoundHostIDs) > 0 {
+		return nil, fmt.Errorf("host IDs not found: %s", strings.Join(notFoundHostIDs, ", "))
+	}
+	return hosts, nil
+}
+
+// GetByGroupIDs returns hosts which belong to any of host groups of groupIDs.
+func GetByGroupIDs(ctx context.Context, c *zabbix.Client,
+	groupIDs []string) ([]Host, error) {
+	params := struct {
+		Output   any `json:"output"`
+		GroupIDs any `json:"groupids"`
+	}{
+		Output:   OutputFields,
+		GroupIDs: groupIDs,
+	}
+	return zabbix.CallTyped[[]Host](ctx, c, "host.get", params)
+}
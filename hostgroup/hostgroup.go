@@ -0,0 +1,100 @@
+// Package hostgroup provides functions for Zabbix host group API.
+// https://www.zabbix.com/documentation/6.0/en/manual/api/reference/hostgroup
+package hostgroup
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"golang.org/x/exp/slices"
+
+	"github.com/hnakamur/go-zabbix"
+)
+
+// https://www.zabbix.com/documentation/6.0/en/manual/api/reference/hostgroup/object
+type HostGroup struct {
+	GroupID string `json:"groupid"`
+	Name    string `json:"name,omitempty"`
+}
+
+// OutputFields is the properties of host groups returned by functions in
+// this package. It can be used for "selectGroups" parameter of other APIs.
+var OutputFields = []string{"groupid", "name"}
+
+// GetByNamesFullMatch returns host groups whose names are one of names.
+// It returns an error if any of names is not found.
+func GetByNamesFullMatch(ctx context.Context, c *zabbix.Client,
+	names []string) ([]HostGroup, error) {
+	type Names struct {
+		Name []string `json:"name"`
+	}
+
+	params := struct {
+		Output any `json:"output"`
+		Filter any `json:"filter"`
+	}{
+		Output: OutputFields,
+		Filter: Names{
+			Name: names,
+		},
+	}
+	groups, err := zabbix.CallTyped[[]HostGroup](ctx, c, "hostgroup.get", params)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkNamesFound(groups, names); err != nil {
+		return nil, err
+	}
+	return groups, nil
+}
+
+// GetNestedByAncestorNames returns host groups whose names are one of names,
+// or start with one of names + "/".
+// It returns an error if any of names is not found.
+func GetNestedByAncestorNames(ctx context.Context, c *zabbix.Client,
+	names []string) ([]HostGroup, error) {
+
+	params := struct {
+		Output any `json:"output"`
+	}{
+		Output: OutputFields,
+	}
+	groups, err := zabbix.CallTyped[[]HostGroup](ctx, c, "hostgroup.get", params)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkNamesFound(groups, names); err != nil {
+		return nil, err
+	}
+	return filterByAncestorNames(groups, names), nil
+}
+
+func checkNamesFound(groups []HostGroup, names []string) error {
+	var notFoundNames []string
+	for _, name := range names {
+		if !slices.ContainsFunc(groups, func(grp HostGroup) bool {
+			return grp.Name == name
+		}) {
+			notFoundNames = append(notFoundNames, name)
+		}
+	}
+	if len(notFoundNames) > 0 {
+		return fmt.Errorf("host groups not found: %s", strings.Join(notFoundNames, ", "))
+	}
+	return nil
+}
+
+func filterByAncestorNames(groups []HostGroup, names []string) []HostGroup {
+	var filteredGroups []HostGroup
+	for _, group := range groups {
+		for _, name := range names {
+			if group.Name == name || strings.HasPrefix(group.Name, name+"/") {
+				filteredGroups = append(filteredGroups, group)
+			}
+		}
+	}
+	return filteredGroups
+}
@@ -0,0 +1,69 @@
+package zabbix
+
+import (
+	"context"
+	"time"
+)
+
+// CallInfo describes a request sent by Client.Call. It is passed to
+// interceptors.
+type CallInfo struct {
+	Method string
+	// Params is the params passed to Client.Call.
+	// Note Params of "user.login" contains the password.
+	Params any
+	// Result is the pointer passed to Client.Call. It is filled by the Invoker.
+	Result any
+
+	// The following fields are set by the Client after the request is sent.
+
+	// RequestID is the JSON-RPC ID of the request.
+	RequestID uint64
+	// StatusCode is the HTTP status code of the response.
+	StatusCode int
+	// Duration is the time taken to send the request and receive the response.
+	Duration time.Duration
+
+	req *rpcRequest
+}
+
+// Invoker sends a request described by call to the server.
+type Invoker func(ctx context.Context, call *CallInfo) error
+
+// Interceptor intercepts requests sent by Client.Call.
+// An interceptor may inspect or modify call, and must call invoker to send
+// the request, unless it returns an error or fills call.Result by itself
+// (for example, fault injection or caching).
+type Interceptor func(ctx context.Context, call *CallInfo, invoker Invoker) error
+
+// WithInterceptors adds interceptors to the Client.
+// Interceptors are called for each request sent by Client.Call, including
+// retries and re-logins. The first interceptor is the outermost one.
+// Batch requests sent by Client.CallBatch are not intercepted.
+func WithInterceptors(interceptors ...Interceptor) ClientOpt {
+	return func(c *Client) {
+		c.interceptors = append(c.interceptors, interceptors...)
+	}
+}
+
+// chainInterceptors returns an Invoker which calls interceptors in order and
+// then invoker.
+func chainInterceptors(interceptors []Interceptor, invoker Invoker) Invoker {
+	for i := len(interceptors) - 1; i >= 0; i-- {
+		interceptor, next := interceptors[i], invoker
+		invoker = func(ctx context.Context, call *CallInfo) error {
+			return interceptor(ctx, call, next)
+		}
+	}
+	return invoker
+}
+
+// debugInterceptor logs requests and responses with secrets hidden.
+// It is added as the innermost interceptor by WithDebug(true).
+func (c *Client) debugInterceptor(ctx context.Context, call *CallInfo, invoker Invoker) error {
+	err := invoker(ctx, call)
+	if call.req != nil {
+		c.debugRPCCall(ctx, call, err)
+	}
+	return err
+}
@@ -0,0 +1,65 @@
+package zabbix
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"golang.org/x/exp/slices"
+)
+
+func TestClientInterceptors(t *testing.T) {
+	var received []testRequest
+	s := newTestServer(t, "6.0.16", &received, func(req testRequest) any {
+		return "3"
+	})
+
+	var calls []string
+	tracer := func(name string) Interceptor {
+		return func(ctx context.Context, call *CallInfo, invoker Invoker) error {
+			calls = append(calls, name+" before "+call.Method)
+			err := invoker(ctx, call)
+			calls = append(calls, name+" after "+call.Method)
+			return err
+		}
+	}
+	errInjected := errors.New("injected")
+	faultInjector := func(ctx context.Context, call *CallInfo, invoker Invoker) error {
+		if call.Method == "host.delete" {
+			return errInjected
+		}
+		return invoker(ctx, call)
+	}
+	client, err := NewClient(s.URL,
+		WithInterceptors(tracer("outer"), tracer("inner")),
+		WithInterceptors(faultInjector))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	var count string
+	if err := client.Call(ctx, "host.get", map[string]bool{"countOutput": true}, &count); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := count, "3"; got != want {
+		t.Errorf("result mismatch, got=%s, want=%s", got, want)
+	}
+	wantCalls := []string{
+		"outer before host.get",
+		"inner before host.get",
+		"inner after host.get",
+		"outer after host.get",
+	}
+	if !slices.Equal(calls, wantCalls) {
+		t.Errorf("calls mismatch, got=%v, want=%v", calls, wantCalls)
+	}
+
+	var ids any
+	if err := client.Call(ctx, "host.delete", []string{"1"}, &ids); !errors.Is(err, errInjected) {
+		t.Errorf("want injected error, got=%v", err)
+	}
+	if got, want := len(received), 1; got != want {
+		t.Errorf("request count mismatch, got=%d, want=%d", got, want)
+	}
+}
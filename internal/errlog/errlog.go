@@ -1,26 +1,47 @@
 package errlog
 
 import (
-	"fmt"
-	"io"
+	"context"
 	"log"
+	"log/slog"
 	"os"
+	"runtime"
+	"time"
+
+	"github.com/hnakamur/go-zabbix/internal/outlog"
 )
 
-var logger = log.New(os.Stderr, "", log.LstdFlags)
+var logger = slog.New(outlog.NewHandler(os.Stderr, outlog.FormatText, log.LstdFlags, slog.LevelInfo))
+
+func Error(msg string, args ...any) {
+	output(slog.LevelError, msg, args...)
+}
+
+func Warn(msg string, args ...any) {
+	output(slog.LevelWarn, msg, args...)
+}
 
-func Print(v ...any) {
-	logger.Output(2, fmt.Sprint(v...))
+func Debug(msg string, args ...any) {
+	output(slog.LevelDebug, msg, args...)
 }
 
-func Printf(format string, v ...any) {
-	logger.Output(2, fmt.Sprintf(format, v...))
+// output logs with the caller of Error, Warn or Debug as the source.
+func output(level slog.Level, msg string, args ...any) {
+	ctx := context.Background()
+	if !logger.Enabled(ctx, level) {
+		return
+	}
+	var pcs [1]uintptr
+	runtime.Callers(3, pcs[:]) // skip [Callers, output, Error, Warn or Debug]
+	r := slog.NewRecord(time.Now(), level, msg, pcs[0])
+	r.Add(args...)
+	_ = logger.Handler().Handle(ctx, r)
 }
 
-func SetFlags(flag int) {
-	logger.SetFlags(flag)
+func Logger() *slog.Logger {
+	return logger
 }
 
-func SetOutput(w io.Writer) {
-	logger.SetOutput(w)
+func SetLogger(l *slog.Logger) {
+	logger = l
 }
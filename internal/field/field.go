@@ -0,0 +1,64 @@
+// Package field provides conversions between Go types and string values of
+// properties in Zabbix API objects.
+package field
+
+import (
+	"strconv"
+	"time"
+)
+
+// Timestamp is a time encoded as a string of seconds from the Unix epoch time.
+type Timestamp time.Time
+
+// ParseTimestamp parses a string of seconds from the Unix epoch time.
+// An empty string is parsed as the zero time.
+func ParseTimestamp(epochSeconds string) (Timestamp, error) {
+	if epochSeconds == "" {
+		return Timestamp{}, nil
+	}
+	ts, err := strconv.ParseInt(epochSeconds, 10, 64)
+	if err != nil {
+		return Timestamp{}, err
+	}
+	return Timestamp(time.Unix(ts, 0)), nil
+}
+
+// String formats t as seconds from the Unix epoch time, or returns an empty
+// string if t is the zero time, so that unset times are omitted.
+func (t Timestamp) String() string {
+	tt := time.Time(t)
+	if tt.IsZero() {
+		return ""
+	}
+	return strconv.FormatInt(tt.Unix(), 10)
+}
+
+// Seconds is a duration encoded as a string of seconds.
+type Seconds time.Duration
+
+// ParseSeconds parses a string of seconds.
+// An empty string is parsed as zero.
+func ParseSeconds(seconds string) (Seconds, error) {
+	if seconds == "" {
+		return 0, nil
+	}
+	s, err := strconv.ParseInt(seconds, 10, 64)
+	if err != nil {
+		return 0, err
+	}
+	return Seconds(time.Duration(s) * time.Second), nil
+}
+
+func (s Seconds) String() string {
+	seconds := int64(time.Duration(s) / time.Second)
+	return strconv.FormatInt(seconds, 10)
+}
+
+// ParseInt parses a string of a decimal integer.
+// An empty string is parsed as zero.
+func ParseInt(s string) (int, error) {
+	if s == "" {
+		return 0, nil
+	}
+	return strconv.Atoi(s)
+}
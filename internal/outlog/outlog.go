@@ -1,30 +1,72 @@
 package outlog
 
 import (
+	"context"
 	"errors"
-	"fmt"
 	"io"
 	"log"
+	"log/slog"
 	"os"
+	"runtime"
 	"strings"
+	"time"
 )
 
-var logger = log.New(os.Stdout, "", log.LstdFlags)
+var logger = slog.New(NewHandler(os.Stdout, FormatText, log.LstdFlags, slog.LevelInfo))
 
-func Print(v ...any) {
-	logger.Output(2, fmt.Sprint(v...))
+func Info(msg string, args ...any) {
+	output(slog.LevelInfo, msg, args...)
 }
 
-func Printf(format string, v ...any) {
-	logger.Output(2, fmt.Sprintf(format, v...))
+func Debug(msg string, args ...any) {
+	output(slog.LevelDebug, msg, args...)
 }
 
-func SetFlags(flag int) {
-	logger.SetFlags(flag)
+// output logs with the caller of Info or Debug as the source.
+func output(level slog.Level, msg string, args ...any) {
+	ctx := context.Background()
+	if !logger.Enabled(ctx, level) {
+		return
+	}
+	var pcs [1]uintptr
+	runtime.Callers(3, pcs[:]) // skip [Callers, output, Info or Debug]
+	r := slog.NewRecord(time.Now(), level, msg, pcs[0])
+	r.Add(args...)
+	_ = logger.Handler().Handle(ctx, r)
+}
+
+func Logger() *slog.Logger {
+	return logger
+}
+
+func SetLogger(l *slog.Logger) {
+	logger = l
+}
+
+// Format is the output format of logs.
+type Format string
+
+const (
+	FormatText Format = "text"
+	FormatJSON Format = "json"
+)
+
+func ParseFormat(s string) (Format, error) {
+	switch f := Format(s); f {
+	case FormatText, FormatJSON:
+		return f, nil
+	default:
+		return "", errors.New(`must be "text" or "json"`)
+	}
 }
 
-func SetOutput(w io.Writer) {
-	logger.SetOutput(w)
+// NewHandler returns a slog.Handler which writes logs in format to w.
+// flags is used only for FormatText and the meaning is the same as log.Logger.
+func NewHandler(w io.Writer, format Format, flags int, level slog.Leveler) slog.Handler {
+	if format == FormatJSON {
+		return slog.NewJSONHandler(w, &slog.HandlerOptions{Level: level})
+	}
+	return newTextHandler(w, flags, level)
 }
 
 func ParseLogFlags(s string) (int, error) {
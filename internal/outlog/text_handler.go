@@ -0,0 +1,156 @@
+package outlog
+
+import (
+	"context"
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"log/slog"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// textHandler is a slog.Handler which writes logs like
+// "2006/01/02 15:04:05 INFO message key1=value1 key2=value2" with log.Logger,
+// so that the timestamp format can be configured with log flags.
+// Values of non-scalar types are written in JSON.
+type textHandler struct {
+	logger   *log.Logger
+	fileFlag int
+	level    slog.Leveler
+
+	// preformatted is attributes added with WithAttrs.
+	preformatted string
+	// groupPrefix is the names of groups added with WithGroup joined with ".".
+	groupPrefix string
+}
+
+var _ slog.Handler = (*textHandler)(nil)
+
+func newTextHandler(w io.Writer, flags int, level slog.Leveler) *textHandler {
+	return &textHandler{
+		// The file name is written by Handle since log.Logger cannot know
+		// the caller of slog.Logger methods.
+		logger:   log.New(w, "", flags&^(log.Lshortfile|log.Llongfile)),
+		fileFlag: flags & (log.Lshortfile | log.Llongfile),
+		level:    level,
+	}
+}
+
+func (h *textHandler) Enabled(_ context.Context, level slog.Level) bool {
+	minLevel := slog.LevelInfo
+	if h.level != nil {
+		minLevel = h.level.Level()
+	}
+	return level >= minLevel
+}
+
+func (h *textHandler) Handle(_ context.Context, r slog.Record) error {
+	var b strings.Builder
+	if h.fileFlag != 0 && r.PC != 0 {
+		frames := runtime.CallersFrames([]uintptr{r.PC})
+		f, _ := frames.Next()
+		file := f.File
+		if h.fileFlag&log.Lshortfile != 0 {
+			file = filepath.Base(file)
+		}
+		fmt.Fprintf(&b, "%s:%d: ", file, f.Line)
+	}
+	b.WriteString(r.Level.String())
+	if r.Message != "" {
+		b.WriteByte(' ')
+		b.WriteString(r.Message)
+	}
+	b.WriteString(h.preformatted)
+	r.Attrs(func(a slog.Attr) bool {
+		appendAttr(&b, h.groupPrefix, a)
+		return true
+	})
+	return h.logger.Output(0, b.String())
+}
+
+func (h *textHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	var b strings.Builder
+	b.WriteString(h.preformatted)
+	for _, a := range attrs {
+		appendAttr(&b, h.groupPrefix, a)
+	}
+	h2 := *h
+	h2.preformatted = b.String()
+	return &h2
+}
+
+func (h *textHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	h2 := *h
+	h2.groupPrefix = h.groupPrefix + name + "."
+	return &h2
+}
+
+func appendAttr(b *strings.Builder, prefix string, a slog.Attr) {
+	a.Value = a.Value.Resolve()
+	if a.Equal(slog.Attr{}) {
+		return
+	}
+	if a.Value.Kind() == slog.KindGroup {
+		if a.Key != "" {
+			prefix += a.Key + "."
+		}
+		for _, ga := range a.Value.Group() {
+			appendAttr(b, prefix, ga)
+		}
+		return
+	}
+	b.WriteByte(' ')
+	b.WriteString(prefix)
+	b.WriteString(a.Key)
+	b.WriteByte('=')
+	b.WriteString(formatValue(a.Value))
+}
+
+func formatValue(v slog.Value) string {
+	switch v.Kind() {
+	case slog.KindString:
+		return formatString(v.String())
+	case slog.KindAny:
+		switch x := v.Any().(type) {
+		case error:
+			return formatString(x.Error())
+		case fmt.Stringer:
+			return formatString(x.String())
+		case encoding.TextMarshaler:
+			text, err := x.MarshalText()
+			if err != nil {
+				return formatString(err.Error())
+			}
+			return formatString(string(text))
+		default:
+			data, err := json.Marshal(x)
+			if err != nil {
+				return formatString(fmt.Sprintf("%+v", x))
+			}
+			return string(data)
+		}
+	default:
+		return formatString(v.String())
+	}
+}
+
+// formatString returns s as is if it is a JSON object or array, or a word
+// without spaces or special characters, or the quoted s otherwise.
+func formatString(s string) string {
+	if (strings.HasPrefix(s, "{") || strings.HasPrefix(s, "[")) && json.Valid([]byte(s)) {
+		return s
+	}
+	if s == "" || strings.ContainsAny(s, " \t\r\n\"=\\") || !utf8.ValidString(s) {
+		return strconv.Quote(s)
+	}
+	return s
+}
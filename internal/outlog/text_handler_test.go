@@ -0,0 +1,62 @@
+package outlog
+
+import (
+	"bytes"
+	"errors"
+	"log/slog"
+	"testing"
+)
+
+func TestTextHandler(t *testing.T) {
+	testCases := []struct {
+		name string
+		log  func(l *slog.Logger)
+		want string
+	}{
+		{
+			name: "scalars",
+			log: func(l *slog.Logger) {
+				l.Info("created maintenance", "maintenance_id", "12", "count", 3)
+			},
+			want: "INFO created maintenance maintenance_id=12 count=3\n",
+		},
+		{
+			name: "quoted",
+			log: func(l *slog.Logger) {
+				l.Info("msg", "name", "a b", "empty", "")
+			},
+			want: `INFO msg name="a b" empty=""` + "\n",
+		},
+		{
+			name: "json",
+			log: func(l *slog.Logger) {
+				l.Info("hosts", "host_names", []string{"host1", "host2"},
+					"err", errors.New(`{"code":-32602}`))
+			},
+			want: `INFO hosts host_names=["host1","host2"] err={"code":-32602}` + "\n",
+		},
+		{
+			name: "group",
+			log: func(l *slog.Logger) {
+				l.With("a", 1).WithGroup("g").Info("msg", "b", 2)
+			},
+			want: "INFO msg a=1 g.b=2\n",
+		},
+		{
+			name: "disabled",
+			log: func(l *slog.Logger) {
+				l.Debug("msg")
+			},
+			want: "",
+		},
+	}
+	for _, c := range testCases {
+		t.Run(c.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			c.log(slog.New(NewHandler(&buf, FormatText, 0, slog.LevelInfo)))
+			if got := buf.String(); got != c.want {
+				t.Errorf("result mismatch, got=%q, want=%q", got, c.want)
+			}
+		})
+	}
+}
@@ -1,7 +0,0 @@
-package rpc
-
-import "github.com/hnakamur/go-zabbix"
-
-type Client struct {
-	*zabbix.Client
-}
@@ -1,8 +1,9 @@
 package slicex
 
 import (
-	"slices"
 	"testing"
+
+	"golang.org/x/exp/slices"
 )
 
 func TestMap(t *testing.T) {
@@ -0,0 +1,40 @@
+// Package item provides types for Zabbix item API.
+// https://www.zabbix.com/documentation/6.0/en/manual/api/reference/item
+package item
+
+// https://www.zabbix.com/documentation/6.0/en/manual/api/reference/item/object
+type Item struct {
+	ItemID string `json:"itemid"`
+	HostID string `json:"hostid"`
+	Key    string `json:"key_"`
+	Name   string `json:"name"`
+	Type   Type   `json:"type"`
+}
+
+// OutputFields is the properties of items returned by functions in other
+// packages. It can be used for "selectItems" parameter of other APIs.
+var OutputFields = []string{"itemid", "hostid", "key_", "name", "type"}
+
+// Type is the type of an item.
+type Type string
+
+const (
+	TypeZabbixAgent       Type = "0"
+	TypeZabbixTrapper     Type = "2"
+	TypeSimpleCheck       Type = "3"
+	TypeZabbixInternal    Type = "5"
+	TypeZabbixAgentActive Type = "7"
+	TypeWebItem           Type = "9"
+	TypeExternalCheck     Type = "10"
+	TypeDatabaseMonitor   Type = "11"
+	TypeIPMIAgent         Type = "12"
+	TypeSSHAgent          Type = "13"
+	TypeTelnetAgent       Type = "14"
+	TypeCalculated        Type = "15"
+	TypeJMXAgent          Type = "16"
+	TypeSNMPTrap          Type = "17"
+	TypeDependentItem     Type = "18"
+	TypeHTTPAgent         Type = "19"
+	TypeSNMPAgent         Type = "20"
+	TypeScript            Type = "21"
+)
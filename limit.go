@@ -0,0 +1,183 @@
+package zabbix
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// WithRateLimit limits the rate of HTTP requests sent by the Client to
+// ratePerSec requests per second, allowing bursts of up to burst requests.
+// If burst is less than 1, it is set to 1. If ratePerSec is not positive,
+// the rate is not limited.
+func WithRateLimit(ratePerSec float64, burst int) ClientOpt {
+	return func(c *Client) {
+		if ratePerSec <= 0 {
+			c.rateLimiter = nil
+			return
+		}
+		if burst < 1 {
+			burst = 1
+		}
+		c.rateLimiter = &rateLimiter{
+			rate:   ratePerSec,
+			burst:  float64(burst),
+			tokens: float64(burst),
+		}
+	}
+}
+
+// WithMaxConcurrentCalls limits the number of in-flight HTTP requests sent by
+// the Client to n. If n is not positive, the number is not limited.
+func WithMaxConcurrentCalls(n int) ClientOpt {
+	return func(c *Client) {
+		if n <= 0 {
+			c.concurrencySem = nil
+			return
+		}
+		c.concurrencySem = make(chan struct{}, n)
+	}
+}
+
+// LimiterStats is statistics of waits caused by WithRateLimit and
+// WithMaxConcurrentCalls.
+type LimiterStats struct {
+	// Requests is the number of requests which passed the limiters.
+	Requests uint64
+	// WaitedRequests is the number of requests which had to wait.
+	WaitedRequests uint64
+	// TotalWait is the sum of waiting time of all requests.
+	TotalWait time.Duration
+	// MaxWait is the longest waiting time of requests.
+	MaxWait time.Duration
+	// InFlight is the number of requests being sent now.
+	InFlight int64
+}
+
+// LimiterStats returns the statistics of the rate and concurrency limiters.
+func (c *Client) LimiterStats() LimiterStats {
+	return LimiterStats{
+		Requests:       c.limiterStats.requests.Load(),
+		WaitedRequests: c.limiterStats.waitedRequests.Load(),
+		TotalWait:      time.Duration(c.limiterStats.totalWait.Load()),
+		MaxWait:        time.Duration(c.limiterStats.maxWait.Load()),
+		InFlight:       c.limiterStats.inFlight.Load(),
+	}
+}
+
+type limiterStats struct {
+	requests       atomic.Uint64
+	waitedRequests atomic.Uint64
+	totalWait      atomic.Int64
+	maxWait        atomic.Int64
+	inFlight       atomic.Int64
+}
+
+func (s *limiterStats) addWait(d time.Duration) {
+	s.requests.Add(1)
+	if d <= 0 {
+		return
+	}
+	s.waitedRequests.Add(1)
+	s.totalWait.Add(int64(d))
+	for {
+		cur := s.maxWait.Load()
+		if int64(d) <= cur || s.maxWait.CompareAndSwap(cur, int64(d)) {
+			return
+		}
+	}
+}
+
+// acquire waits until a request is allowed by the limiters.
+// The caller must call the returned release function after the request is
+// completed if err is nil.
+func (c *Client) acquire(ctx context.Context) (release func(), err error) {
+	start := time.Now()
+	var waited bool
+	if c.rateLimiter != nil {
+		w, err := c.rateLimiter.wait(ctx)
+		if err != nil {
+			return nil, err
+		}
+		waited = w
+	}
+	if c.concurrencySem != nil {
+		select {
+		case c.concurrencySem <- struct{}{}:
+		default:
+			waited = true
+			select {
+			case c.concurrencySem <- struct{}{}:
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+		}
+	}
+	var wait time.Duration
+	if waited {
+		wait = time.Since(start)
+	}
+	c.limiterStats.addWait(wait)
+	c.limiterStats.inFlight.Add(1)
+	return func() {
+		c.limiterStats.inFlight.Add(-1)
+		if c.concurrencySem != nil {
+			<-c.concurrencySem
+		}
+	}, nil
+}
+
+// rateLimiter is a token bucket rate limiter.
+type rateLimiter struct {
+	rate  float64
+	burst float64
+
+	mu     sync.Mutex
+	tokens float64
+	last   time.Time
+}
+
+// wait takes a token, waiting until it is available or ctx is done.
+// It returns whether it waited.
+func (l *rateLimiter) wait(ctx context.Context) (waited bool, err error) {
+	d := l.reserve(time.Now())
+	if d <= 0 {
+		return false, nil
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		l.cancel()
+		return true, ctx.Err()
+	case <-timer.C:
+		return true, nil
+	}
+}
+
+// reserve takes a token and returns the duration to wait until the token
+// becomes available.
+func (l *rateLimiter) reserve(now time.Time) time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if !l.last.IsZero() {
+		l.tokens += now.Sub(l.last).Seconds() * l.rate
+		if l.tokens > l.burst {
+			l.tokens = l.burst
+		}
+	}
+	l.last = now
+	l.tokens--
+	if l.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-l.tokens / l.rate * float64(time.Second))
+}
+
+// cancel returns a token taken by reserve.
+func (l *rateLimiter) cancel() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.tokens++
+}
@@ -0,0 +1,100 @@
+package zabbix
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestRateLimiterReserve(t *testing.T) {
+	l := &rateLimiter{rate: 10, burst: 2, tokens: 2}
+	now := time.Now()
+	testCases := []struct {
+		elapsed time.Duration
+		want    time.Duration
+	}{
+		{elapsed: 0, want: 0},
+		{elapsed: 0, want: 0},
+		{elapsed: 0, want: 100 * time.Millisecond},
+		{elapsed: 0, want: 200 * time.Millisecond},
+		{elapsed: time.Second, want: 0},
+	}
+	for i, c := range testCases {
+		now = now.Add(c.elapsed)
+		if got := l.reserve(now); got != c.want {
+			t.Errorf("wait mismatch, i=%d, got=%s, want=%s", i, got, c.want)
+		}
+	}
+}
+
+func TestClientMaxConcurrentCalls(t *testing.T) {
+	block := make(chan struct{})
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-block
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"jsonrpc":"2.0","result":"6.0.16","id":1}`))
+	}))
+	defer s.Close()
+	defer close(block)
+
+	client, err := NewClient(s.URL, WithMaxConcurrentCalls(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	go func() {
+		var ver string
+		client.Call(context.Background(), apiVersionMethod, []string{}, &ver)
+	}()
+	for client.LimiterStats().InFlight != 1 {
+		time.Sleep(time.Millisecond)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	var ver string
+	err = client.Call(ctx, apiVersionMethod, []string{}, &ver)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("want deadline exceeded error, got=%v", err)
+	}
+}
+
+func TestClientUnlimited(t *testing.T) {
+	testCases := []struct {
+		name string
+		opt  ClientOpt
+	}{
+		{name: "zeroRate", opt: WithRateLimit(0, 1)},
+		{name: "negativeRate", opt: WithRateLimit(-1, 1)},
+		{name: "zeroConcurrentCalls", opt: WithMaxConcurrentCalls(0)},
+		{name: "negativeConcurrentCalls", opt: WithMaxConcurrentCalls(-1)},
+	}
+	for _, c := range testCases {
+		t.Run(c.name, func(t *testing.T) {
+			var received []testRequest
+			s := newTestServer(t, "6.0.16", &received, func(req testRequest) any { return "3" })
+			client, err := NewClient(s.URL, c.opt)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if client.rateLimiter != nil || client.concurrencySem != nil {
+				t.Error("limiters must not be set")
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			for i := 0; i < 3; i++ {
+				var count string
+				if err := client.Call(ctx, "host.get", nil, &count); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if got, want := client.LimiterStats().WaitedRequests, uint64(0); got != want {
+				t.Errorf("waited requests mismatch, got=%d, want=%d", got, want)
+			}
+		})
+	}
+}
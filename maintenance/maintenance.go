@@ -0,0 +1,430 @@
+// Package maintenance provides functions for Zabbix maintenance API.
+// https://www.zabbix.com/documentation/6.0/en/manual/api/reference/maintenance
+package maintenance
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/hnakamur/go-zabbix"
+	"github.com/hnakamur/go-zabbix/host"
+	"github.com/hnakamur/go-zabbix/hostgroup"
+	"github.com/hnakamur/go-zabbix/internal/field"
+	"github.com/hnakamur/go-zabbix/internal/slicex"
+)
+
+// https://www.zabbix.com/documentation/6.0/en/manual/api/reference/maintenance/object
+type Maintenance struct {
+	MaintenanceID   string
+	Name            string
+	ActiveSince     time.Time
+	ActiveTill      time.Time
+	Description     string
+	MaintenanceType host.MaintenanceType
+	TagsEvalType    TagsEvalType
+	Groups          []hostgroup.HostGroup
+	Hosts           []host.Host
+	TimePeriods     []TimePeriod
+	// Tags is the problem tags. Tags can be set only when MaintenanceType is
+	// host.MaintenanceTypeWithData. Tags are left unchanged by Update if Tags
+	// is nil, and removed if Tags is empty.
+	Tags []ProblemTag
+}
+
+type TagsEvalType string
+
+const (
+	TagsEvalTypeAndOr TagsEvalType = "0"
+	TagsEvalTypeOr    TagsEvalType = "2"
+)
+
+// ProblemTag is a problem tag of a maintenance. Only problems with the
+// matching tags are suppressed by the maintenance.
+type ProblemTag struct {
+	Tag      string      `json:"tag"`
+	Operator TagOperator `json:"operator"`
+	Value    string      `json:"value"`
+}
+
+type TagOperator string
+
+const (
+	TagOperatorEquals   TagOperator = "0"
+	TagOperatorContains TagOperator = "2"
+)
+
+type rawMaintenance struct {
+	MaintenanceID   string                `json:"maintenanceid,omitempty"`
+	Name            string                `json:"name,omitempty"`
+	ActiveSince     string                `json:"active_since,omitempty"`
+	ActiveTill      string                `json:"active_till,omitempty"`
+	Description     string                `json:"description,omitempty"`
+	MaintenanceType string                `json:"maintenance_type,omitempty"`
+	TagsEvalType    string                `json:"tags_evaltype,omitempty"`
+	Groups          []hostgroup.HostGroup `json:"groups"`
+	Hosts           []host.Host           `json:"hosts"`
+	TimePeriods     []TimePeriod          `json:"timeperiods,omitempty"`
+	// Tags is a pointer to distinguish nil from empty.
+	Tags *[]ProblemTag `json:"tags,omitempty"`
+}
+
+func (m Maintenance) MarshalJSON() ([]byte, error) {
+	var tags *[]ProblemTag
+	if m.Tags != nil {
+		tags = &m.Tags
+	}
+	return json.Marshal(rawMaintenance{
+		MaintenanceID:   m.MaintenanceID,
+		Name:            m.Name,
+		ActiveSince:     field.Timestamp(m.ActiveSince).String(),
+		ActiveTill:      field.Timestamp(m.ActiveTill).String(),
+		Description:     m.Description,
+		MaintenanceType: string(m.MaintenanceType),
+		TagsEvalType:    string(m.TagsEvalType),
+		Groups:          m.Groups,
+		Hosts:           m.Hosts,
+		TimePeriods:     m.TimePeriods,
+		Tags:            tags,
+	})
+}
+
+func (m *Maintenance) UnmarshalJSON(data []byte) error {
+	var r rawMaintenance
+	if err := json.Unmarshal(data, &r); err != nil {
+		return err
+	}
+	activeSince, err := field.ParseTimestamp(r.ActiveSince)
+	if err != nil {
+		return err
+	}
+	activeTill, err := field.ParseTimestamp(r.ActiveTill)
+	if err != nil {
+		return err
+	}
+
+	var tags []ProblemTag
+	if r.Tags != nil {
+		tags = *r.Tags
+	}
+
+	*m = Maintenance{
+		MaintenanceID:   r.MaintenanceID,
+		Name:            r.Name,
+		ActiveSince:     time.Time(activeSince),
+		ActiveTill:      time.Time(activeTill),
+		Description:     r.Description,
+		MaintenanceType: host.MaintenanceType(r.MaintenanceType),
+		TagsEvalType:    TagsEvalType(r.TagsEvalType),
+		Groups:          r.Groups,
+		Hosts:           r.Hosts,
+		TimePeriods:     r.TimePeriods,
+		Tags:            tags,
+	}
+	return nil
+}
+
+type TimeperiodType string
+
+const (
+	TimeperiodTypeOnetimeOnly TimeperiodType = "0"
+	TimeperiodTypeDaily       TimeperiodType = "2"
+	TimeperiodTypeWeekly      TimeperiodType = "3"
+	TimeperiodTypeMonthly     TimeperiodType = "4"
+)
+
+// TimePeriod is a time period of a maintenance.
+//
+// StartDate is used only for TimeperiodTypeOnetimeOnly, and StartTime,
+// Every, DayOfWeek, Day and Month are used only for the other types.
+type TimePeriod struct {
+	TimeperiodID   string
+	Period         time.Duration
+	TimeperiodType TimeperiodType
+	StartDate      time.Time
+
+	// StartTime is the time of day when the maintenance starts.
+	StartTime time.Duration
+	// Every is the interval in days for daily periods and in weeks for weekly
+	// periods. For monthly periods with DayOfWeek, it is the week of the
+	// month: 1 to 4 for the first to the fourth week, and 5 for the last week.
+	Every int
+	// DayOfWeek is the days of the week for weekly and monthly periods.
+	DayOfWeek DayOfWeek
+	// Day is the day of the month for monthly periods.
+	Day int
+	// Month is the months for monthly periods.
+	Month Month
+}
+
+// DayOfWeek is a bitmask of days of the week.
+type DayOfWeek int
+
+const (
+	DayOfWeekMonday DayOfWeek = 1 << iota
+	DayOfWeekTuesday
+	DayOfWeekWednesday
+	DayOfWeekThursday
+	DayOfWeekFriday
+	DayOfWeekSaturday
+	DayOfWeekSunday
+)
+
+// Month is a bitmask of months.
+type Month int
+
+const (
+	MonthJanuary Month = 1 << iota
+	MonthFebruary
+	MonthMarch
+	MonthApril
+	MonthMay
+	MonthJune
+	MonthJuly
+	MonthAugust
+	MonthSeptember
+	MonthOctober
+	MonthNovember
+	MonthDecember
+)
+
+type rawTimePeriod struct {
+	TimeperiodID   string `json:"timeperiodid,omitempty"`
+	Period         string `json:"period"`
+	TimeperiodType string `json:"timeperiod_type"`
+	StartDate      string `json:"start_date,omitempty"`
+	StartTime      string `json:"start_time,omitempty"`
+	Every          string `json:"every,omitempty"`
+	DayOfWeek      string `json:"dayofweek,omitempty"`
+	Day            string `json:"day,omitempty"`
+	Month          string `json:"month,omitempty"`
+}
+
+// OutputTimePeriodFields is the properties of time periods returned by
+// functions in this package.
+var OutputTimePeriodFields = []string{"timeperiodid", "period", "timeperiod_type",
+	"start_date", "start_time", "every", "dayofweek", "day", "month"}
+
+func (p TimePeriod) MarshalJSON() ([]byte, error) {
+	r := rawTimePeriod{
+		TimeperiodID:   p.TimeperiodID,
+		Period:         field.Seconds(p.Period).String(),
+		TimeperiodType: string(p.TimeperiodType),
+	}
+	// Send only the properties used for the type, since the rest are
+	// returned by the API with meaningless values.
+	if p.TimeperiodType == TimeperiodTypeOnetimeOnly {
+		r.StartDate = field.Timestamp(p.StartDate).String()
+	} else {
+		r.StartTime = field.Seconds(p.StartTime).String()
+		r.Every = formatNonZero(p.Every)
+		r.DayOfWeek = formatNonZero(int(p.DayOfWeek))
+		r.Day = formatNonZero(p.Day)
+		r.Month = formatNonZero(int(p.Month))
+	}
+	return json.Marshal(r)
+}
+
+func (p *TimePeriod) UnmarshalJSON(data []byte) error {
+	var r rawTimePeriod
+	if err := json.Unmarshal(data, &r); err != nil {
+		return err
+	}
+	period, err := field.ParseSeconds(r.Period)
+	if err != nil {
+		return err
+	}
+	startDate, err := field.ParseTimestamp(r.StartDate)
+	if err != nil {
+		return err
+	}
+	startTime, err := field.ParseSeconds(r.StartTime)
+	if err != nil {
+		return err
+	}
+	every, err := field.ParseInt(r.Every)
+	if err != nil {
+		return err
+	}
+	dayOfWeek, err := field.ParseInt(r.DayOfWeek)
+	if err != nil {
+		return err
+	}
+	day, err := field.ParseInt(r.Day)
+	if err != nil {
+		return err
+	}
+	month, err := field.ParseInt(r.Month)
+	if err != nil {
+		return err
+	}
+
+	*p = TimePeriod{
+		TimeperiodID:   r.TimeperiodID,
+		Period:         time.Duration(period),
+		TimeperiodType: TimeperiodType(r.TimeperiodType),
+		StartDate:      time.Time(startDate),
+		StartTime:      time.Duration(startTime),
+		Every:          every,
+		DayOfWeek:      DayOfWeek(dayOfWeek),
+		Day:            day,
+		Month:          Month(month),
+	}
+	return nil
+}
+
+// formatNonZero returns an empty string for zero so that the property is
+// omitted.
+func formatNonZero(i int) string {
+	if i == 0 {
+		return ""
+	}
+	return strconv.Itoa(i)
+}
+
+type getParams struct {
+	Output            any `json:"output"`
+	SelectGroups      any `json:"selectGroups"`
+	SelectHosts       any `json:"selectHosts"`
+	SelectTimeperiods any `json:"selectTimeperiods"`
+	SelectTags        any `json:"selectTags"`
+	Filter            any `json:"filter,omitempty"`
+}
+
+func newGetParams(filter any) getParams {
+	return getParams{
+		Output:            "extend",
+		SelectGroups:      hostgroup.OutputFields,
+		SelectHosts:       host.OutputFields,
+		SelectTimeperiods: OutputTimePeriodFields,
+		SelectTags:        "extend",
+		Filter:            filter,
+	}
+}
+
+// GetAll returns all maintenances.
+func GetAll(ctx context.Context, c *zabbix.Client) ([]Maintenance, error) {
+	return zabbix.CallTyped[[]Maintenance](ctx, c, "maintenance.get", newGetParams(nil))
+}
+
+// GetByID returns the maintenance of maintenanceID.
+func GetByID(ctx context.Context, c *zabbix.Client, maintenanceID string) (*Maintenance, error) {
+	type Filter struct {
+		MaintenanceID []string `json:"maintenanceid"`
+	}
+
+	params := newGetParams(Filter{MaintenanceID: []string{maintenanceID}})
+	return getOne(ctx, c, params)
+}
+
+// GetByNameFullMatch returns the maintenance whose name is name.
+func GetByNameFullMatch(ctx context.Context, c *zabbix.Client, name string) (*Maintenance, error) {
+	type Names struct {
+		Name []string `json:"name"`
+	}
+
+	params := newGetParams(Names{Name: []string{name}})
+	return getOne(ctx, c, params)
+}
+
+func getOne(ctx context.Context, c *zabbix.Client, params getParams) (*Maintenance, error) {
+	ms, err := zabbix.CallTyped[[]Maintenance](ctx, c, "maintenance.get", params)
+	if err != nil {
+		return nil, err
+	}
+	if len(ms) != 1 {
+		return nil, fmt.Errorf("unexpected maintenance count, got=%d, want=1", len(ms))
+	}
+	return &ms[0], nil
+}
+
+type maintenanceIDs struct {
+	MaintenanceIDs []string `json:"maintenanceids"`
+}
+
+// Create creates a maintenance and sets the ID of the created maintenance to
+// m.MaintenanceID.
+func Create(ctx context.Context, c *zabbix.Client, m *Maintenance) error {
+	ids, err := zabbix.CallTyped[maintenanceIDs](ctx, c, "maintenance.create", m)
+	if err != nil {
+		return err
+	}
+	if len(ids.MaintenanceIDs) != 1 {
+		return fmt.Errorf("unexpected ids length: %d", len(ids.MaintenanceIDs))
+	}
+	m.MaintenanceID = ids.MaintenanceIDs[0]
+	return nil
+}
+
+// Update updates the maintenance of m.MaintenanceID.
+func Update(ctx context.Context, c *zabbix.Client, m *Maintenance) error {
+	ids, err := zabbix.CallTyped[maintenanceIDs](ctx, c, "maintenance.update", m)
+	if err != nil {
+		return err
+	}
+	if len(ids.MaintenanceIDs) != 1 {
+		return fmt.Errorf("unexpected ids length: %d", len(ids.MaintenanceIDs))
+	}
+	return nil
+}
+
+// GetIDsByIDs returns maintenanceIDs if all of them exist.
+func GetIDsByIDs(ctx context.Context, c *zabbix.Client, maintenanceIDs []string) ([]string, error) {
+	type rpcFilter struct {
+		MaintenanceID []string `json:"maintenanceid"`
+	}
+
+	params := struct {
+		Output any `json:"output"`
+		Filter any `json:"filter"`
+	}{
+		Output: "maintenanceid",
+		Filter: rpcFilter{MaintenanceID: maintenanceIDs},
+	}
+	ms, err := zabbix.CallTyped[[]Maintenance](ctx, c, "maintenance.get", params)
+	if err != nil {
+		return nil, err
+	}
+	if len(ms) != len(maintenanceIDs) {
+		return nil, fmt.Errorf("unexpected maintenance count returned by GetIDsByIDs: got=%d, want=%d", len(ms), len(maintenanceIDs))
+	}
+	return slicex.Map(ms, func(m Maintenance) string {
+		return m.MaintenanceID
+	}), nil
+}
+
+// GetIDsByNamesFullMatch returns IDs of maintenances whose names are names.
+func GetIDsByNamesFullMatch(ctx context.Context, c *zabbix.Client, names []string) ([]string, error) {
+	type rpcFilter struct {
+		Name []string `json:"name"`
+	}
+
+	params := struct {
+		Output any `json:"output"`
+		Filter any `json:"filter"`
+	}{
+		Output: "maintenanceid",
+		Filter: rpcFilter{Name: names},
+	}
+	ms, err := zabbix.CallTyped[[]Maintenance](ctx, c, "maintenance.get", params)
+	if err != nil {
+		return nil, err
+	}
+	if len(ms) != len(names) {
+		return nil, fmt.Errorf("unexpected maintenance count returned by GetIDsByNamesFullMatch: got=%d, want=%d", len(ms), len(names))
+	}
+	return slicex.Map(ms, func(m Maintenance) string {
+		return m.MaintenanceID
+	}), nil
+}
+
+// DeleteByIDs deletes maintenances and returns IDs of deleted ones.
+func DeleteByIDs(ctx context.Context, c *zabbix.Client, ids []string) (deletedIDs []string, err error) {
+	result, err := zabbix.CallTyped[maintenanceIDs](ctx, c, "maintenance.delete", ids)
+	if err != nil {
+		return nil, err
+	}
+	return result.MaintenanceIDs, nil
+}
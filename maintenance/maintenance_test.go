@@ -0,0 +1,167 @@
+package maintenance
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/hnakamur/go-zabbix/host"
+	"github.com/hnakamur/go-zabbix/hostgroup"
+)
+
+func TestMaintenanceJSON(t *testing.T) {
+	m := Maintenance{
+		MaintenanceID:   "3",
+		Name:            "test",
+		ActiveSince:     time.Unix(1700000000, 0),
+		ActiveTill:      time.Unix(1700003600, 0),
+		MaintenanceType: host.MaintenanceTypeNoData,
+		TagsEvalType:    TagsEvalTypeAndOr,
+		Groups:          []hostgroup.HostGroup{{GroupID: "2"}},
+		Hosts:           []host.Host{{HostID: "10084", Name: "server"}},
+		TimePeriods: []TimePeriod{{
+			Period:         time.Hour,
+			TimeperiodType: TimeperiodTypeOnetimeOnly,
+			StartDate:      time.Unix(1700000000, 0),
+		}},
+		Tags: []ProblemTag{
+			{Tag: "service", Operator: TagOperatorEquals, Value: "web"},
+			{Tag: "env", Operator: TagOperatorContains, Value: "prod"},
+		},
+	}
+	data, err := json.Marshal(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"maintenanceid":"3","name":"test","active_since":"1700000000",` +
+		`"active_till":"1700003600","maintenance_type":"1","tags_evaltype":"0",` +
+		`"groups":[{"groupid":"2"}],"hosts":[{"hostid":"10084","name":"server"}],` +
+		`"timeperiods":[{"period":"3600","timeperiod_type":"0","start_date":"1700000000"}],` +
+		`"tags":[{"tag":"service","operator":"0","value":"web"},{"tag":"env","operator":"2","value":"prod"}]}`
+	if got := string(data); got != want {
+		t.Errorf("marshal result mismatch,\n got=%s,\nwant=%s", got, want)
+	}
+
+	var got Maintenance
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, m) {
+		t.Errorf("unmarshal result mismatch,\n got=%+v,\nwant=%+v", got, m)
+	}
+}
+
+func TestMaintenanceTagsJSON(t *testing.T) {
+	testCases := []struct {
+		tags []ProblemTag
+		want string
+	}{
+		// Tags are left unchanged.
+		{tags: nil, want: `{"maintenanceid":"3","active_since":"1700000000",` +
+			`"active_till":"1700003600","groups":null,"hosts":null}`},
+		// Tags are removed.
+		{tags: []ProblemTag{}, want: `{"maintenanceid":"3","active_since":"1700000000",` +
+			`"active_till":"1700003600","groups":null,"hosts":null,"tags":[]}`},
+	}
+	for _, c := range testCases {
+		m := Maintenance{
+			MaintenanceID: "3",
+			ActiveSince:   time.Unix(1700000000, 0),
+			ActiveTill:    time.Unix(1700003600, 0),
+			Tags:          c.tags,
+		}
+		data, err := json.Marshal(m)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := string(data); got != c.want {
+			t.Errorf("marshal result mismatch,\n got=%s,\nwant=%s", got, c.want)
+		}
+	}
+}
+
+func TestTimePeriodJSON(t *testing.T) {
+	testCases := []struct {
+		tp   TimePeriod
+		want string
+	}{
+		{
+			tp: TimePeriod{
+				Period:         2 * time.Hour,
+				TimeperiodType: TimeperiodTypeDaily,
+				StartTime:      3 * time.Hour,
+				Every:          2,
+			},
+			want: `{"period":"7200","timeperiod_type":"2","start_time":"10800","every":"2"}`,
+		},
+		{
+			tp: TimePeriod{
+				TimeperiodID:   "5",
+				Period:         time.Hour,
+				TimeperiodType: TimeperiodTypeWeekly,
+				Every:          1,
+				DayOfWeek:      DayOfWeekMonday | DayOfWeekFriday,
+			},
+			want: `{"timeperiodid":"5","period":"3600","timeperiod_type":"3","start_time":"0","every":"1","dayofweek":"17"}`,
+		},
+		{
+			tp: TimePeriod{
+				Period:         time.Hour,
+				TimeperiodType: TimeperiodTypeMonthly,
+				StartTime:      30 * time.Minute,
+				Day:            15,
+				Month:          MonthJanuary | MonthJuly,
+			},
+			want: `{"period":"3600","timeperiod_type":"4","start_time":"1800","day":"15","month":"65"}`,
+		},
+		{
+			tp: TimePeriod{
+				Period:         time.Hour,
+				TimeperiodType: TimeperiodTypeMonthly,
+				Every:          5,
+				DayOfWeek:      DayOfWeekSunday,
+				Month:          MonthDecember,
+			},
+			want: `{"period":"3600","timeperiod_type":"4","start_time":"0","every":"5","dayofweek":"64","month":"2048"}`,
+		},
+		{
+			// The start date is omitted if it is not set, e.g. for updates.
+			tp: TimePeriod{
+				Period:         time.Hour,
+				TimeperiodType: TimeperiodTypeOnetimeOnly,
+			},
+			want: `{"period":"3600","timeperiod_type":"0"}`,
+		},
+	}
+	for _, c := range testCases {
+		data, err := json.Marshal(c.tp)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := string(data); got != c.want {
+			t.Errorf("marshal result mismatch,\n got=%s,\nwant=%s", got, c.want)
+		}
+
+		var got TimePeriod
+		if err := json.Unmarshal(data, &got); err != nil {
+			t.Fatal(err)
+		}
+		if got != c.tp {
+			t.Errorf("unmarshal result mismatch,\n got=%+v,\nwant=%+v", got, c.tp)
+		}
+	}
+}
+
+func TestMaintenanceJSONZeroTimes(t *testing.T) {
+	m := Maintenance{MaintenanceID: "1", Name: "maintenance1"}
+	data, err := json.Marshal(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The active period is omitted if it is not set, e.g. for updates.
+	want := `{"maintenanceid":"1","name":"maintenance1","groups":null,"hosts":null}`
+	if got := string(data); got != want {
+		t.Errorf("marshal result mismatch,\n got=%s,\nwant=%s", got, want)
+	}
+}
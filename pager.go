@@ -0,0 +1,174 @@
+package zabbix
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// CallTyped sends a JSON-RPC request to the server and returns the result
+// decoded into T.
+func CallTyped[T any](ctx context.Context, c *Client, method string, params any) (T, error) {
+	var result T
+	if err := c.Call(ctx, method, params, &result); err != nil {
+		return result, err
+	}
+	return result, nil
+}
+
+// DefaultPageSize is the page size used by NewPager if pageSize is not
+// positive.
+const DefaultPageSize = 1000
+
+// Pager iterates over the results of a "*.get" method page by page, so that
+// large results are not received in one giant response.
+//
+// Since Zabbix API supports neither offsets nor range filters, Pager first
+// gets only the IDs of all objects sorted by the ID, and then uses them as
+// a cursor to get pageSize objects at a time with the "<idField>s" parameter
+// (e.g. "hostids").
+//
+// The usage is like bufio.Scanner:
+//
+//	p := zabbix.NewPager[Host](client, "host.get", params, "hostid", 1000)
+//	for p.Next(ctx) {
+//		for _, h := range p.Page() {
+//			...
+//		}
+//	}
+//	if err := p.Err(); err != nil {
+//		...
+//	}
+type Pager[T any] struct {
+	client   *Client
+	method   string
+	params   map[string]any
+	idField  string
+	pageSize int
+
+	ids     []string
+	idsRead bool
+	page    []T
+	err     error
+}
+
+// NewPager creates a Pager. params must be encoded to a JSON object, and
+// must not contain "limit", "sortfield", "sortorder", or "<idField>s".
+func NewPager[T any](c *Client, method string, params any, idField string, pageSize int) *Pager[T] {
+	if pageSize <= 0 {
+		pageSize = DefaultPageSize
+	}
+	p := &Pager[T]{
+		client:   c,
+		method:   method,
+		idField:  idField,
+		pageSize: pageSize,
+	}
+	p.params, p.err = toParamsMap(params)
+	return p
+}
+
+// Next gets the next page. It returns false when there are no more pages or
+// an error occurred.
+func (p *Pager[T]) Next(ctx context.Context) bool {
+	if p.err != nil {
+		return false
+	}
+	if !p.idsRead {
+		p.ids, p.err = p.getIDs(ctx)
+		if p.err != nil {
+			return false
+		}
+		p.idsRead = true
+	}
+	if len(p.ids) == 0 {
+		p.page = nil
+		return false
+	}
+
+	n := p.pageSize
+	if n > len(p.ids) {
+		n = len(p.ids)
+	}
+	params := p.paramsWith(map[string]any{
+		p.idField + "s": p.ids[:n],
+		"sortfield":     p.idField,
+		"sortorder":     "ASC",
+		"limit":         n,
+	})
+	p.page, p.err = CallTyped[[]T](ctx, p.client, p.method, params)
+	if p.err != nil {
+		return false
+	}
+	p.ids = p.ids[n:]
+	return true
+}
+
+// Page returns the current page.
+func (p *Pager[T]) Page() []T {
+	return p.page
+}
+
+// Err returns the first error occurred in Next.
+func (p *Pager[T]) Err() error {
+	return p.err
+}
+
+func (p *Pager[T]) getIDs(ctx context.Context) ([]string, error) {
+	params := p.paramsWith(map[string]any{
+		"output":    []string{p.idField},
+		"sortfield": p.idField,
+		"sortorder": "ASC",
+	})
+	// Other properties like "selectHosts" are ignored to reduce the response size.
+	for k := range params {
+		if strings.HasPrefix(k, "select") {
+			delete(params, k)
+		}
+	}
+	objs, err := CallTyped[[]map[string]any](ctx, p.client, p.method, params)
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]string, len(objs))
+	for i, obj := range objs {
+		id, ok := obj[p.idField].(string)
+		if !ok {
+			return nil, fmt.Errorf("%s is missing or not a string in result of %s", p.idField, p.method)
+		}
+		ids[i] = id
+	}
+	return ids, nil
+}
+
+// paramsWith returns a copy of p.params with values overwritten.
+func (p *Pager[T]) paramsWith(values map[string]any) map[string]any {
+	params := make(map[string]any, len(p.params)+len(values))
+	for k, v := range p.params {
+		params[k] = v
+	}
+	for k, v := range values {
+		params[k] = v
+	}
+	return params
+}
+
+func toParamsMap(params any) (map[string]any, error) {
+	if params == nil {
+		return map[string]any{}, nil
+	}
+	data, err := json.Marshal(params)
+	if err != nil {
+		return nil, err
+	}
+	var m map[string]any
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, errors.New("params for Pager must be encoded to a JSON object")
+	}
+	if m == nil {
+		m = map[string]any{}
+	}
+	return m, nil
+}
@@ -0,0 +1,102 @@
+package zabbix
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"golang.org/x/exp/slices"
+)
+
+func TestPager(t *testing.T) {
+	type host struct {
+		HostID string `json:"hostid"`
+		Name   string `json:"name"`
+	}
+	var allHosts []host
+	for i := 1; i <= 5; i++ {
+		allHosts = append(allHosts, host{HostID: fmt.Sprint(i), Name: fmt.Sprintf("host%d", i)})
+	}
+
+	var received []testRequest
+	s := newTestServer(t, "6.0.16", &received, func(req testRequest) any {
+		var params struct {
+			HostIDs     []string `json:"hostids"`
+			Limit       *int     `json:"limit"`
+			SelectItems any      `json:"selectItems"`
+		}
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			t.Errorf("decode params: %v", err)
+		}
+		if params.HostIDs == nil {
+			if params.SelectItems != nil {
+				t.Error("selectItems must be removed in request for IDs")
+			}
+			var ids []map[string]string
+			for _, h := range allHosts {
+				ids = append(ids, map[string]string{"hostid": h.HostID})
+			}
+			return ids
+		}
+		if params.Limit == nil || *params.Limit != len(params.HostIDs) {
+			t.Errorf("limit must be the number of hostids, params=%s", req.Params)
+		}
+		var hosts []host
+		for _, h := range allHosts {
+			if slices.Contains(params.HostIDs, h.HostID) {
+				hosts = append(hosts, h)
+			}
+		}
+		return hosts
+	})
+	client, err := NewClient(s.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	params := map[string]any{"output": []string{"hostid", "name"}, "selectItems": "count"}
+	p := NewPager[host](client, "host.get", params, "hostid", 2)
+	var pageSizes []int
+	var got []host
+	for p.Next(ctx) {
+		pageSizes = append(pageSizes, len(p.Page()))
+		got = append(got, p.Page()...)
+	}
+	if err := p.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(got, allHosts) {
+		t.Errorf("hosts mismatch, got=%v, want=%v", got, allHosts)
+	}
+	if want := []int{2, 2, 1}; !slices.Equal(pageSizes, want) {
+		t.Errorf("page sizes mismatch, got=%v, want=%v", pageSizes, want)
+	}
+	// The IDs are got in one request and the hosts in one request per page.
+	if got, want := len(received), 4; got != want {
+		t.Errorf("request count mismatch, got=%d, want=%d", got, want)
+	}
+}
+
+func TestCallTyped(t *testing.T) {
+	var received []testRequest
+	s := newTestServer(t, "6.0.16", &received, func(req testRequest) any {
+		return []map[string]string{{"groupid": "1", "name": "Linux servers"}}
+	})
+	client, err := NewClient(s.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	type hostGroup struct {
+		GroupID string `json:"groupid"`
+		Name    string `json:"name"`
+	}
+	groups, err := CallTyped[[]hostGroup](context.Background(), client, "hostgroup.get", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []hostGroup{{GroupID: "1", Name: "Linux servers"}}; !slices.Equal(groups, want) {
+		t.Errorf("result mismatch, got=%v, want=%v", groups, want)
+	}
+}
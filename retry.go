@@ -0,0 +1,124 @@
+package zabbix
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// RetryPolicy configures retries of calls which failed with transient errors.
+// Transient errors are HTTP transport errors, HTTPErrors with 5xx or 429
+// status codes (e.g. an error page of a reverse proxy), and responses whose
+// body is broken JSON.
+// Errors returned from Zabbix API are never retried.
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of attempts including the first one.
+	// The default is 3.
+	MaxAttempts int
+
+	// InitialBackoff is the delay before the first retry. The default is 500ms.
+	InitialBackoff time.Duration
+
+	// MaxBackoff is the upper limit of delays. The default is 10s.
+	MaxBackoff time.Duration
+
+	// Multiplier is the factor by which the delay is multiplied after each
+	// retry. The default is 2.
+	Multiplier float64
+
+	// Jitter is the fraction of the delay which is randomly subtracted.
+	// It must be between 0 and 1. The default is 0.2.
+	Jitter float64
+
+	// RetryableMethod returns whether calls of the method may be retried.
+	// The default is DefaultRetryableMethod. Set a function which returns
+	// true for methods like "maintenance.create" to retry non-idempotent
+	// methods explicitly.
+	RetryableMethod func(method string) bool
+}
+
+// WithRetryPolicy enables retries of calls which failed with transient
+// errors. The zero values in policy are replaced with the defaults.
+func WithRetryPolicy(policy RetryPolicy) ClientOpt {
+	return func(c *Client) {
+		if policy.MaxAttempts == 0 {
+			policy.MaxAttempts = 3
+		}
+		if policy.InitialBackoff == 0 {
+			policy.InitialBackoff = 500 * time.Millisecond
+		}
+		if policy.MaxBackoff == 0 {
+			policy.MaxBackoff = 10 * time.Second
+		}
+		if policy.Multiplier == 0 {
+			policy.Multiplier = 2
+		}
+		if policy.Jitter == 0 {
+			policy.Jitter = 0.2
+		}
+		if policy.RetryableMethod == nil {
+			policy.RetryableMethod = DefaultRetryableMethod
+		}
+		c.retryPolicy = &policy
+	}
+}
+
+// DefaultRetryableMethod returns true for idempotent methods, that is,
+// "*.get" methods and "apiinfo.version".
+func DefaultRetryableMethod(method string) bool {
+	return strings.HasSuffix(method, ".get") || method == apiVersionMethod
+}
+
+func (p *RetryPolicy) shouldRetry(method string, req *rpcRequest, err error, attempt int) bool {
+	return attempt < p.MaxAttempts && p.RetryableMethod(method) &&
+		isTransientError(req, err)
+}
+
+// backoff returns the delay before the retry after the attempt.
+func (p *RetryPolicy) backoff(attempt int) time.Duration {
+	d := float64(p.InitialBackoff) * math.Pow(p.Multiplier, float64(attempt-1))
+	if d > float64(p.MaxBackoff) {
+		d = float64(p.MaxBackoff)
+	}
+	d -= d * p.Jitter * rand.Float64()
+	return time.Duration(d)
+}
+
+// sleep waits for the backoff delay or returns the error of ctx if ctx is
+// done before that.
+func (p *RetryPolicy) sleep(ctx context.Context, attempt int) error {
+	timer := time.NewTimer(p.backoff(attempt))
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+func isTransientError(req *rpcRequest, err error) bool {
+	if req == nil || GetErrorCode(err) != ErrorCodeNone {
+		return false
+	}
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		return true
+	}
+	var httpErr *HTTPError
+	if errors.As(err, &httpErr) {
+		return httpErr.StatusCode >= http.StatusInternalServerError ||
+			httpErr.StatusCode == http.StatusTooManyRequests
+	}
+	var syntaxErr *json.SyntaxError
+	return errors.As(err, &syntaxErr)
+}
@@ -0,0 +1,97 @@
+package zabbix
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestClientRetryPolicy(t *testing.T) {
+	testCases := []struct {
+		name      string
+		method    string
+		retryable func(method string) bool
+		wantCount int
+		wantErr   bool
+	}{
+		{name: "get", method: "host.get", wantCount: 3, wantErr: false},
+		{name: "updateDefault", method: "host.update", wantCount: 1, wantErr: true},
+		{name: "updateOptIn", method: "host.update",
+			retryable: func(string) bool { return true }, wantCount: 3, wantErr: false},
+	}
+	for _, c := range testCases {
+		t.Run(c.name, func(t *testing.T) {
+			var count int
+			s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				count++
+				if count < 3 {
+					w.Header().Set("Content-Type", "text/html")
+					w.WriteHeader(http.StatusBadGateway)
+					w.Write([]byte("<html><body>502 Bad Gateway</body></html>"))
+					return
+				}
+				var req testRequest
+				if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+					t.Errorf("decode request: %v", err)
+					return
+				}
+				w.Header().Set("Content-Type", "application/json")
+				json.NewEncoder(w).Encode(map[string]any{
+					"jsonrpc": jsonrpcVersion,
+					"result":  "ok",
+					"id":      req.ID,
+				})
+			}))
+			defer s.Close()
+
+			client, err := NewClient(s.URL, WithRetryPolicy(RetryPolicy{
+				MaxAttempts:     3,
+				InitialBackoff:  time.Millisecond,
+				RetryableMethod: c.retryable,
+			}))
+			if err != nil {
+				t.Fatal(err)
+			}
+			var result string
+			err = client.Call(context.Background(), c.method, []string{}, &result)
+			if c.wantErr {
+				if err == nil {
+					t.Error("want error but got no error")
+				}
+			} else if err != nil {
+				t.Fatal(err)
+			}
+			if got, want := count, c.wantCount; got != want {
+				t.Errorf("request count mismatch, got=%d, want=%d", got, want)
+			}
+		})
+	}
+}
+
+func TestRetryPolicyBackoff(t *testing.T) {
+	var c Client
+	WithRetryPolicy(RetryPolicy{
+		InitialBackoff: 100 * time.Millisecond,
+		MaxBackoff:     300 * time.Millisecond,
+		Jitter:         0.5,
+	})(&c)
+	testCases := []struct {
+		attempt int
+		max     time.Duration
+	}{
+		{attempt: 1, max: 100 * time.Millisecond},
+		{attempt: 2, max: 200 * time.Millisecond},
+		{attempt: 3, max: 300 * time.Millisecond},
+		{attempt: 10, max: 300 * time.Millisecond},
+	}
+	for _, tc := range testCases {
+		got := c.retryPolicy.backoff(tc.attempt)
+		if got > tc.max || got < tc.max/2 {
+			t.Errorf("backoff out of range, attempt=%d, got=%s, want=[%s, %s]",
+				tc.attempt, got, tc.max/2, tc.max)
+		}
+	}
+}
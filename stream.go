@@ -0,0 +1,125 @@
+package zabbix
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// ErrResponseTooLarge is the error returned when the size of a response body
+// exceeds the limit set with WithMaxResponseSize.
+var ErrResponseTooLarge = errors.New("response body exceeds the maximum size")
+
+// WithMaxResponseSize limits the size of response bodies to maxBytes.
+// Calls whose response exceeds the limit fail with ErrResponseTooLarge.
+// If maxBytes is 0 or negative, the size is not limited (the default).
+func WithMaxResponseSize(maxBytes int64) ClientOpt {
+	return func(c *Client) {
+		c.maxResponseSize = maxBytes
+	}
+}
+
+// CallEach sends a JSON-RPC request whose result is an array, and calls fn
+// with each element decoded into T while reading the response, so that the
+// whole result does not need to be kept in memory.
+// If fn returns an error, CallEach stops reading and returns the error
+// wrapped in a CallError. Calls are not retried once fn has been called.
+func CallEach[T any](ctx context.Context, c *Client, method string, params any, fn func(elem T) error) error {
+	s := &resultStreamer{
+		decodeElem: func(dec *json.Decoder) error {
+			var elem T
+			if err := dec.Decode(&elem); err != nil {
+				return err
+			}
+			return fn(elem)
+		},
+	}
+	return c.Call(ctx, method, params, s)
+}
+
+// resultStreamer is passed as the result to Client.Call by CallEach to
+// decode the result array element by element.
+type resultStreamer struct {
+	decodeElem func(dec *json.Decoder) error
+	// started is set to true before the first element is decoded.
+	started bool
+}
+
+func (s *resultStreamer) decodeResponse(r io.Reader, res *responseCommon) error {
+	dec := json.NewDecoder(r)
+	if err := expectDelim(dec, '{'); err != nil {
+		return err
+	}
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		switch tok {
+		case "jsonrpc":
+			err = dec.Decode(&res.Jsonrpc)
+		case "id":
+			err = dec.Decode(&res.ID)
+		case "error":
+			err = dec.Decode(&res.Error)
+		case "result":
+			err = s.decodeResult(dec)
+		default:
+			var skipped json.RawMessage
+			err = dec.Decode(&skipped)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return expectDelim(dec, '}')
+}
+
+func (s *resultStreamer) decodeResult(dec *json.Decoder) error {
+	if err := expectDelim(dec, '['); err != nil {
+		return fmt.Errorf("result is not an array: %w", err)
+	}
+	for dec.More() {
+		s.started = true
+		if err := s.decodeElem(dec); err != nil {
+			return err
+		}
+	}
+	return expectDelim(dec, ']')
+}
+
+func expectDelim(dec *json.Decoder, want json.Delim) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if got, ok := tok.(json.Delim); !ok || got != want {
+		return fmt.Errorf("unexpected JSON token: got=%v, want=%v", tok, want)
+	}
+	return nil
+}
+
+// maxSizeReader is a reader which returns ErrResponseTooLarge if more than
+// remaining bytes are read.
+type maxSizeReader struct {
+	r         io.Reader
+	remaining int64
+}
+
+func (r *maxSizeReader) Read(p []byte) (n int, err error) {
+	if r.remaining < 0 {
+		return 0, ErrResponseTooLarge
+	}
+	// Read one more byte than remaining to detect the excess.
+	if int64(len(p)) > r.remaining+1 {
+		p = p[:r.remaining+1]
+	}
+	n, err = r.r.Read(p)
+	r.remaining -= int64(n)
+	if r.remaining < 0 {
+		return n + int(r.remaining), ErrResponseTooLarge
+	}
+	return n, err
+}
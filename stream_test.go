@@ -0,0 +1,85 @@
+package zabbix
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"golang.org/x/exp/slices"
+)
+
+func TestCallEach(t *testing.T) {
+	type item struct {
+		ItemID string `json:"itemid"`
+	}
+	items := []item{{ItemID: "1"}, {ItemID: "2"}, {ItemID: "3"}}
+
+	var received []testRequest
+	s := newTestServer(t, "6.0.16", &received, func(req testRequest) any {
+		return items
+	})
+
+	for _, debug := range []bool{false, true} {
+		client, err := NewClient(s.URL, WithDebug(debug))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		ctx := context.Background()
+		var got []item
+		if err := CallEach(ctx, client, "item.get", nil, func(it item) error {
+			got = append(got, it)
+			return nil
+		}); err != nil {
+			t.Fatal(err)
+		}
+		if !slices.Equal(got, items) {
+			t.Errorf("items mismatch, debug=%v, got=%v, want=%v", debug, got, items)
+		}
+
+		errStop := errors.New("stop")
+		var count int
+		err = CallEach(ctx, client, "item.get", nil, func(it item) error {
+			count++
+			return errStop
+		})
+		if !errors.Is(err, errStop) {
+			t.Errorf("want stop error, got=%v", err)
+		}
+		if count != 1 {
+			t.Errorf("callback count mismatch, got=%d, want=1", count)
+		}
+	}
+}
+
+func TestClientMaxResponseSize(t *testing.T) {
+	var received []testRequest
+	s := newTestServer(t, "6.0.16", &received, func(req testRequest) any {
+		return strings.Repeat("x", 1000)
+	})
+
+	testCases := []struct {
+		maxSize int64
+		wantErr bool
+	}{
+		{maxSize: 0, wantErr: false},
+		{maxSize: 2000, wantErr: false},
+		{maxSize: 100, wantErr: true},
+	}
+	for _, c := range testCases {
+		client, err := NewClient(s.URL, WithMaxResponseSize(c.maxSize))
+		if err != nil {
+			t.Fatal(err)
+		}
+		var result string
+		err = client.Call(context.Background(), "host.get", nil, &result)
+		if c.wantErr {
+			if !errors.Is(err, ErrResponseTooLarge) {
+				t.Errorf("want ErrResponseTooLarge, maxSize=%d, got=%v", c.maxSize, err)
+			}
+		} else if err != nil {
+			t.Errorf("unexpected error, maxSize=%d, err=%v", c.maxSize, err)
+		}
+	}
+}
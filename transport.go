@@ -0,0 +1,172 @@
+package zabbix
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+)
+
+// transportOptions is the options to configure the transport of the HTTP
+// client.
+type transportOptions struct {
+	caCertFile         string
+	clientCertFile     string
+	clientKeyFile      string
+	insecureSkipVerify bool
+	proxyURL           string
+}
+
+func (o *transportOptions) isZero() bool {
+	return *o == transportOptions{}
+}
+
+// WithCACertFile makes the Client trust CA certificates in the PEM file in
+// addition to the system ones. This is useful for the Zabbix frontend with
+// a certificate issued by an internal CA.
+func WithCACertFile(path string) ClientOpt {
+	return func(c *Client) {
+		c.transportOpts.caCertFile = path
+	}
+}
+
+// WithClientCertFile makes the Client send the client certificate for mutual
+// TLS authentication. certFile and keyFile are PEM files.
+func WithClientCertFile(certFile, keyFile string) ClientOpt {
+	return func(c *Client) {
+		c.transportOpts.clientCertFile = certFile
+		c.transportOpts.clientKeyFile = keyFile
+	}
+}
+
+// WithInsecureSkipVerify disables verification of the server certificate.
+// This should be used only for testing.
+func WithInsecureSkipVerify(skip bool) ClientOpt {
+	return func(c *Client) {
+		c.transportOpts.insecureSkipVerify = skip
+	}
+}
+
+// WithProxy makes the Client send requests via the HTTP proxy of proxyURL
+// (ex. http://proxy.example.com:8080). The default is the proxy specified
+// with environment variables like HTTPS_PROXY.
+func WithProxy(proxyURL string) ClientOpt {
+	return func(c *Client) {
+		c.transportOpts.proxyURL = proxyURL
+	}
+}
+
+// WithHeader adds a header to requests. It can be specified multiple times.
+func WithHeader(key, value string) ClientOpt {
+	return func(c *Client) {
+		if c.header == nil {
+			c.header = make(http.Header)
+		}
+		c.header.Add(key, value)
+	}
+}
+
+// WithBasicAuth sets the Authorization header for the basic authentication,
+// which is required by some reverse proxies in front of the Zabbix frontend.
+// Since the header cannot be used for the auth of Zabbix API at the same
+// time, AuthMethodAuto selects AuthMethodField with this option.
+// Zabbix 7.2 removed the "auth" property, so with Zabbix 7.2 or later,
+// calls which require the auth fail with ErrAuthHeaderInUse.
+func WithBasicAuth(username, password string) ClientOpt {
+	return func(c *Client) {
+		if c.header == nil {
+			c.header = make(http.Header)
+		}
+		credentials := base64.StdEncoding.EncodeToString([]byte(username + ":" + password))
+		c.header.Set("Authorization", "Basic "+credentials)
+	}
+}
+
+// WithTimeout sets the time limit for each HTTP request including reading
+// the response body. The default is no timeout.
+func WithTimeout(timeout time.Duration) ClientOpt {
+	return func(c *Client) {
+		c.timeout = timeout
+	}
+}
+
+// configureHTTPClient sets c.httpClient to a copy of the HTTP client with
+// transport options and the timeout applied.
+func (c *Client) configureHTTPClient() error {
+	if c.httpClient == nil {
+		c.httpClient = http.DefaultClient
+	}
+	if c.transportOpts.isZero() && c.timeout == 0 {
+		return nil
+	}
+
+	httpClient := *c.httpClient
+	if !c.transportOpts.isZero() {
+		transport, err := c.transportOpts.newTransport(httpClient.Transport)
+		if err != nil {
+			return err
+		}
+		httpClient.Transport = transport
+	}
+	if c.timeout > 0 {
+		httpClient.Timeout = c.timeout
+	}
+	c.httpClient = &httpClient
+	return nil
+}
+
+// newTransport returns a clone of base with the options applied.
+func (o *transportOptions) newTransport(base http.RoundTripper) (*http.Transport, error) {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	baseTransport, ok := base.(*http.Transport)
+	if !ok {
+		return nil, fmt.Errorf("TLS and proxy options cannot be used with the transport of type %T", base)
+	}
+	t := baseTransport.Clone()
+	if t.TLSClientConfig == nil {
+		t.TLSClientConfig = &tls.Config{}
+	}
+
+	if o.caCertFile != "" {
+		pem, err := os.ReadFile(o.caCertFile)
+		if err != nil {
+			return nil, err
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in CA certificate file: %s", o.caCertFile)
+		}
+		t.TLSClientConfig.RootCAs = pool
+	}
+	if o.clientCertFile != "" || o.clientKeyFile != "" {
+		if o.clientCertFile == "" || o.clientKeyFile == "" {
+			return nil, errors.New("both of client certificate and key files must be specified")
+		}
+		cert, err := tls.LoadX509KeyPair(o.clientCertFile, o.clientKeyFile)
+		if err != nil {
+			return nil, err
+		}
+		t.TLSClientConfig.Certificates = []tls.Certificate{cert}
+	}
+	if o.insecureSkipVerify {
+		t.TLSClientConfig.InsecureSkipVerify = true
+	}
+	if o.proxyURL != "" {
+		u, err := url.Parse(o.proxyURL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid proxy URL: %w", err)
+		}
+		t.Proxy = http.ProxyURL(u)
+	}
+	return t, nil
+}
@@ -0,0 +1,189 @@
+package zabbix
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func newTLSTestServer(t *testing.T, clientAuth tls.ClientAuthType) *httptest.Server {
+	t.Helper()
+	s := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req testRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("decode request: %v", err)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]any{
+			"jsonrpc": jsonrpcVersion,
+			"result":  "6.0.16",
+			"id":      req.ID,
+		})
+	}))
+	s.TLS = &tls.Config{ClientAuth: clientAuth}
+	s.StartTLS()
+	t.Cleanup(s.Close)
+	return s
+}
+
+// writeTestCert writes a self-signed certificate and its key to PEM files.
+func writeTestCert(t *testing.T, dir string, der []byte, key *ecdsa.PrivateKey) (certFile, keyFile string) {
+	t.Helper()
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	certFile = filepath.Join(dir, "cert.pem")
+	keyFile = filepath.Join(dir, "key.pem")
+	if err := os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return certFile, keyFile
+}
+
+func TestClientTLSOptions(t *testing.T) {
+	dir := t.TempDir()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, err := x509.CreateCertificate(rand.Reader, &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "zbx client"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}, &x509.Certificate{SerialNumber: big.NewInt(1)}, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	clientCertFile, clientKeyFile := writeTestCert(t, dir, der, key)
+
+	s := newTLSTestServer(t, tls.RequireAnyClientCert)
+	caCertFile := filepath.Join(dir, "ca.pem")
+	if err := os.WriteFile(caCertFile, pem.EncodeToMemory(&pem.Block{
+		Type: "CERTIFICATE", Bytes: s.Certificate().Raw}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		name    string
+		opts    []ClientOpt
+		wantErr bool
+	}{
+		{name: "noCA", opts: []ClientOpt{
+			WithClientCertFile(clientCertFile, clientKeyFile)}, wantErr: true},
+		{name: "noClientCert", opts: []ClientOpt{
+			WithCACertFile(caCertFile)}, wantErr: true},
+		{name: "caAndClientCert", opts: []ClientOpt{
+			WithCACertFile(caCertFile),
+			WithClientCertFile(clientCertFile, clientKeyFile)}, wantErr: false},
+		{name: "insecureSkipVerify", opts: []ClientOpt{
+			WithInsecureSkipVerify(true),
+			WithClientCertFile(clientCertFile, clientKeyFile)}, wantErr: false},
+	}
+	for _, c := range testCases {
+		t.Run(c.name, func(t *testing.T) {
+			client, err := NewClient(s.URL, c.opts...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			_, err = client.APIVersion(context.Background())
+			if c.wantErr {
+				if err == nil {
+					t.Error("want error but got no error")
+				}
+			} else if err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
+
+func TestClientHeaderOptions(t *testing.T) {
+	var received []testRequest
+	s := newTestServer(t, "6.4.0", &received, func(req testRequest) any { return "3" })
+
+	client, err := NewClient(s.URL, WithAPIToken("token1"),
+		WithBasicAuth("proxyuser", "proxypass"), WithHeader("X-Test", "test1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var count string
+	if err := client.Call(context.Background(), "host.get", map[string]bool{"countOutput": true}, &count); err != nil {
+		t.Fatal(err)
+	}
+	for _, req := range received {
+		h := req.Header
+		if got, want := h.Get("X-Test"), "test1"; got != want {
+			t.Errorf("X-Test header mismatch, got=%q, want=%q", got, want)
+		}
+		r := http.Request{Header: h}
+		if username, password, ok := r.BasicAuth(); !ok || username != "proxyuser" || password != "proxypass" {
+			t.Errorf("basic auth mismatch, got=%q, %q, %v", username, password, ok)
+		}
+	}
+	// The auth must be sent in the "auth" property since the Authorization
+	// header is used for the basic authentication.
+	if got, want := received[len(received)-1].Auth, "token1"; got != want {
+		t.Errorf("auth mismatch, got=%q, want=%q", got, want)
+	}
+}
+
+func TestClientBasicAuthWithoutAuthField(t *testing.T) {
+	var received []testRequest
+	s := newTestServer(t, "7.2.0", &received, func(req testRequest) any { return "3" })
+
+	client, err := NewClient(s.URL, WithAPIToken("token1"), WithBasicAuth("proxyuser", "proxypass"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var count string
+	err = client.Call(context.Background(), "host.get", map[string]bool{"countOutput": true}, &count)
+	if !errors.Is(err, ErrAuthHeaderInUse) {
+		t.Errorf("error mismatch, got=%v, want=%v", err, ErrAuthHeaderInUse)
+	}
+	for _, req := range received {
+		if req.Method == "host.get" {
+			t.Errorf("host.get must not be sent")
+		}
+	}
+}
+
+func TestClientTimeout(t *testing.T) {
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(200 * time.Millisecond):
+		}
+	}))
+	defer s.Close()
+
+	client, err := NewClient(s.URL, WithTimeout(10*time.Millisecond))
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = client.APIVersion(context.Background())
+	var netErr interface{ Timeout() bool }
+	if !errors.As(err, &netErr) || !netErr.Timeout() {
+		t.Errorf("want timeout error, got=%v", err)
+	}
+}
@@ -0,0 +1,199 @@
+// Package trigger provides functions for Zabbix trigger API.
+// https://www.zabbix.com/documentation/6.0/en/manual/api/reference/trigger
+package trigger
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/hnakamur/go-zabbix"
+	"github.com/hnakamur/go-zabbix/host"
+	"github.com/hnakamur/go-zabbix/hostgroup"
+	"github.com/hnakamur/go-zabbix/internal/field"
+	"github.com/hnakamur/go-zabbix/internal/slicex"
+	"github.com/hnakamur/go-zabbix/item"
+)
+
+// https://www.zabbix.com/documentation/6.0/en/manual/api/reference/trigger/object
+type Trigger struct {
+	TriggerID   string
+	Description string
+	Expression  string
+	EventName   string
+	Comments    string
+	Error       string
+	LastChange  time.Time
+	State       State
+	Status      Status
+	URL         string
+	Value       Value
+	Groups      []hostgroup.HostGroup
+	Hosts       []host.Host
+	Items       []item.Item
+}
+
+type State string
+
+const (
+	StateNormal  State = "0"
+	StateUnknown State = "1"
+)
+
+type Status string
+
+const (
+	StatusEnabled  Status = "0"
+	StatusDisabled Status = "1"
+)
+
+type Value string
+
+const (
+	ValueOK      Value = "0"
+	ValueProblem Value = "1"
+)
+
+type rawTrigger struct {
+	TriggerID   string                `json:"triggerid,omitempty"`
+	Description string                `json:"description,omitempty"`
+	Expression  string                `json:"expression,omitempty"`
+	EventName   string                `json:"event_name,omitempty"`
+	Comments    string                `json:"comments,omitempty"`
+	Error       string                `json:"error,omitempty"`
+	LastChange  string                `json:"lastchange,omitempty"`
+	State       string                `json:"state,omitempty"`
+	Status      string                `json:"status,omitempty"`
+	URL         string                `json:"url,omitempty"`
+	Value       string                `json:"value,omitempty"`
+	Groups      []hostgroup.HostGroup `json:"groups,omitempty"`
+	Hosts       []host.Host           `json:"hosts,omitempty"`
+	Items       []item.Item           `json:"items,omitempty"`
+}
+
+// MarshalJSON encodes only writable properties.
+func (t Trigger) MarshalJSON() ([]byte, error) {
+	return json.Marshal(rawTrigger{
+		TriggerID:   t.TriggerID,
+		Description: t.Description,
+		Expression:  t.Expression,
+		EventName:   t.EventName,
+		Comments:    t.Comments,
+		Status:      string(t.Status),
+		URL:         t.URL,
+		// Keep empty values for readonly properties
+	})
+}
+
+func (t *Trigger) UnmarshalJSON(data []byte) error {
+	var r rawTrigger
+	if err := json.Unmarshal(data, &r); err != nil {
+		return err
+	}
+	lastChange, err := field.ParseTimestamp(r.LastChange)
+	if err != nil {
+		return err
+	}
+
+	*t = Trigger{
+		TriggerID:   r.TriggerID,
+		Description: r.Description,
+		Expression:  r.Expression,
+		EventName:   r.EventName,
+		Comments:    r.Comments,
+		Error:       r.Error,
+		LastChange:  time.Time(lastChange),
+		State:       State(r.State),
+		Status:      Status(r.Status),
+		URL:         r.URL,
+		Value:       Value(r.Value),
+		Groups:      r.Groups,
+		Hosts:       r.Hosts,
+		Items:       r.Items,
+	}
+	return nil
+}
+
+// GetOptions is the conditions to get triggers. Empty conditions are ignored.
+type GetOptions struct {
+	TriggerIDs   []string
+	HostIDs      []string
+	GroupIDs     []string
+	ItemIDs      []string
+	Descriptions []string
+}
+
+// Get returns triggers which match all conditions in opts.
+func Get(ctx context.Context, c *zabbix.Client, opts GetOptions) ([]Trigger, error) {
+	type descriptionsFilter struct {
+		Descriptions []string `json:"description"`
+	}
+
+	var filter *descriptionsFilter
+	if len(opts.Descriptions) > 0 {
+		filter = &descriptionsFilter{
+			Descriptions: opts.Descriptions,
+		}
+	}
+	params := struct {
+		TriggerIDs   []string            `json:"triggerids,omitempty"`
+		Output       string              `json:"output"`
+		Filter       *descriptionsFilter `json:"filter,omitempty"`
+		HostIDs      []string            `json:"hostids,omitempty"`
+		GroupIDs     []string            `json:"groupids,omitempty"`
+		ItemIDs      []string            `json:"itemids,omitempty"`
+		SelectGroups []string            `json:"selectGroups"`
+		SelectHosts  []string            `json:"selectHosts"`
+		SelectItems  []string            `json:"selectItems"`
+	}{
+		TriggerIDs:   opts.TriggerIDs,
+		Output:       "extend",
+		Filter:       filter,
+		HostIDs:      opts.HostIDs,
+		GroupIDs:     opts.GroupIDs,
+		ItemIDs:      opts.ItemIDs,
+		SelectGroups: hostgroup.OutputFields,
+		SelectHosts:  host.OutputFields,
+		SelectItems:  item.OutputFields,
+	}
+	return zabbix.CallTyped[[]Trigger](ctx, c, "trigger.get", params)
+}
+
+// GetIDs returns IDs of triggers which match all conditions in opts.
+func GetIDs(ctx context.Context, c *zabbix.Client, opts GetOptions) ([]string, error) {
+	triggers, err := Get(ctx, c, opts)
+	if err != nil {
+		return nil, err
+	}
+	return slicex.Map(triggers, func(t Trigger) string {
+		return t.TriggerID
+	}), nil
+}
+
+// SetStatus updates status of triggers with a single batch request.
+// It returns the IDs of updated triggers even if some updates failed.
+func SetStatus(ctx context.Context, c *zabbix.Client, triggerIDs []string, status Status) ([]string, error) {
+	type TriggerIDs struct {
+		TriggerIDs []string `json:"triggerids"`
+	}
+	type Params struct {
+		TriggerID string `json:"triggerid"`
+		Status    string `json:"status"`
+	}
+
+	var b zabbix.Batch
+	results := make([]TriggerIDs, len(triggerIDs))
+	for i, triggerID := range triggerIDs {
+		b.Add("trigger.update", Params{
+			TriggerID: triggerID,
+			Status:    string(status),
+		}, &results[i])
+	}
+	err := c.CallBatch(ctx, &b)
+
+	var updatedIDs []string
+	for _, ids := range results {
+		updatedIDs = append(updatedIDs, ids.TriggerIDs...)
+	}
+	return updatedIDs, err
+}
@@ -0,0 +1,52 @@
+package zabbixtest
+
+import "fmt"
+
+// apiError is an error object in JSON-RPC responses.
+type apiError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+	Data    string `json:"data"`
+}
+
+func (e *apiError) Error() string {
+	return fmt.Sprintf("%s %s", e.Message, e.Data)
+}
+
+const noPermissions = "No permissions to referred object or it does not exist!"
+
+func errParse() *apiError {
+	return &apiError{Code: -32700, Message: "Parse error.",
+		Data: "Invalid JSON. An error occurred on the server while parsing the JSON text."}
+}
+
+func errMethodNotFound(method string) *apiError {
+	return &apiError{Code: -32601, Message: "Method not found.",
+		Data: fmt.Sprintf("Incorrect method %q.", method)}
+}
+
+func errInvalidParams(format string, args ...any) *apiError {
+	return &apiError{Code: -32602, Message: "Invalid params.",
+		Data: fmt.Sprintf(format, args...)}
+}
+
+func errApplication(format string, args ...any) *apiError {
+	return &apiError{Code: -32500, Message: "Application error.",
+		Data: fmt.Sprintf(format, args...)}
+}
+
+func errNotAuthorised() *apiError {
+	return errInvalidParams("Not authorised.")
+}
+
+func errSessionTerminated() *apiError {
+	return errInvalidParams("Session terminated, re-login, please.")
+}
+
+func errNoPermissions() *apiError {
+	return errApplication(noPermissions)
+}
+
+func errMissingParam(index int, name string) *apiError {
+	return errInvalidParams("Invalid parameter \"/%d\": the parameter %q is missing.", index+1, name)
+}
@@ -0,0 +1,248 @@
+package zabbixtest
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+)
+
+type host struct {
+	props    object
+	groupIDs []string
+}
+
+// AddHost adds a host which belongs to host groups of groupIDs, and returns
+// its ID. It panics if the host cannot be created.
+func (s *Server) AddHost(name string, groupIDs ...string) (hostID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	groups := make([]idObject, len(groupIDs))
+	for i, id := range groupIDs {
+		groups[i] = idObject{"groupid": flexString(id)}
+	}
+	ids, err := s.createHosts([]hostParams{{Host: ptr(flexString(name)), Groups: &groups}})
+	if err != nil {
+		panic(fmt.Sprintf("zabbixtest: add host: %v", err))
+	}
+	return ids[0]
+}
+
+type hostParams struct {
+	HostID *flexString `json:"hostid"`
+	Host   *flexString `json:"host"`
+	Name   *flexString `json:"name"`
+	Status *flexString `json:"status"`
+	Groups *[]idObject `json:"groups"`
+}
+
+// hostProps returns the properties of h including ones of maintenance.
+func (s *Server) hostProps(h *host) object {
+	props := h.props.clone()
+	props["maintenance_status"] = "0"
+	props["maintenance_type"] = "0"
+	props["maintenanceid"] = "0"
+	props["maintenance_from"] = "0"
+	if m, from := s.hostMaintenance(h); m != nil {
+		props["maintenance_status"] = "1"
+		props["maintenance_type"] = m.props["maintenance_type"]
+		props["maintenanceid"] = m.props["maintenanceid"]
+		props["maintenance_from"] = strconv.FormatInt(from, 10)
+	}
+	return props
+}
+
+func (s *Server) hostByName(name string) *host {
+	for _, h := range s.hosts {
+		if h.props["host"] == name {
+			return h
+		}
+	}
+	return nil
+}
+
+// hostObjects returns hosts of hostIDs which exist.
+func (s *Server) hostObjects(hostIDs []string) []object {
+	objs := []object{}
+	for _, id := range hostIDs {
+		if h, ok := s.hosts[id]; ok {
+			objs = append(objs, s.hostProps(h))
+		}
+	}
+	sortObjects(objs, "hostid", nil, nil)
+	return objs
+}
+
+func (s *Server) hostIDsInGroup(groupID string) []string {
+	var ids []string
+	for id, h := range s.hosts {
+		if stringList(h.groupIDs).contains(groupID) {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+func (s *Server) checkHostsExist(hostIDs []string) error {
+	for _, id := range hostIDs {
+		if _, ok := s.hosts[id]; !ok {
+			return errNoPermissions()
+		}
+	}
+	return nil
+}
+
+func (s *Server) hostGet(params json.RawMessage) (any, error) {
+	p, err := decodeGetParams(params)
+	if err != nil {
+		return nil, err
+	}
+	objs := []object{}
+	for _, h := range s.hosts {
+		props := s.hostProps(h)
+		if !idsMatch(p.HostIDs, props["hostid"]) || !idsMatchAny(p.GroupIDs, h.groupIDs) ||
+			!p.match(props) {
+			continue
+		}
+		objs = append(objs, props)
+	}
+	return p.result(objs, "hostid", func(obj object, res map[string]any) error {
+		h := s.hosts[obj["hostid"]]
+		return selectRelated(res, "groups", p.SelectGroups, s.hostGroupObjects(h.groupIDs))
+	})
+}
+
+func (s *Server) hostCreate(params json.RawMessage) (any, error) {
+	hosts, err := decodeOneOrMany[hostParams](params)
+	if err != nil {
+		return nil, err
+	}
+	ids, err := s.createHosts(hosts)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]any{"hostids": ids}, nil
+}
+
+func (s *Server) createHosts(hosts []hostParams) ([]string, error) {
+	groupIDsList := make([][]string, len(hosts))
+	for i, h := range hosts {
+		if h.Host == nil || *h.Host == "" {
+			return nil, errMissingParam(i, "host")
+		}
+		if h.Groups == nil || len(*h.Groups) == 0 {
+			return nil, errMissingParam(i, "groups")
+		}
+		groupIDs, err := toIDs(*h.Groups, "groupid")
+		if err != nil {
+			return nil, err
+		}
+		if err := s.checkHostGroupsExist(groupIDs); err != nil {
+			return nil, err
+		}
+		if s.hostByName(string(*h.Host)) != nil {
+			return nil, errInvalidParams("Host with the same name %q already exists.", *h.Host)
+		}
+		groupIDsList[i] = groupIDs
+	}
+	ids := make([]string, len(hosts))
+	for i, h := range hosts {
+		id := s.newID("host")
+		name := *h.Host
+		if h.Name != nil && *h.Name != "" {
+			name = *h.Name
+		}
+		status := flexString("0")
+		if h.Status != nil {
+			status = *h.Status
+		}
+		s.hosts[id] = &host{
+			props: object{
+				"hostid": id,
+				"host":   string(*h.Host),
+				"name":   string(name),
+				"status": string(status),
+			},
+			groupIDs: groupIDsList[i],
+		}
+		ids[i] = id
+	}
+	return ids, nil
+}
+
+func (s *Server) hostUpdate(params json.RawMessage) (any, error) {
+	hosts, err := decodeOneOrMany[hostParams](params)
+	if err != nil {
+		return nil, err
+	}
+	groupIDsList := make([][]string, len(hosts))
+	for i, h := range hosts {
+		if h.HostID == nil {
+			return nil, errMissingParam(i, "hostid")
+		}
+		if _, ok := s.hosts[string(*h.HostID)]; !ok {
+			return nil, errNoPermissions()
+		}
+		if h.Host != nil {
+			if other := s.hostByName(string(*h.Host)); other != nil &&
+				other.props["hostid"] != string(*h.HostID) {
+				return nil, errInvalidParams("Host with the same name %q already exists.", *h.Host)
+			}
+		}
+		if h.Groups != nil {
+			groupIDs, err := toIDs(*h.Groups, "groupid")
+			if err != nil {
+				return nil, err
+			}
+			if len(groupIDs) == 0 {
+				return nil, errInvalidParams("Host %q cannot be without host group.",
+					s.hosts[string(*h.HostID)].props["host"])
+			}
+			if err := s.checkHostGroupsExist(groupIDs); err != nil {
+				return nil, err
+			}
+			groupIDsList[i] = groupIDs
+		}
+	}
+	ids := make([]string, len(hosts))
+	for i, h := range hosts {
+		id := string(*h.HostID)
+		target := s.hosts[id]
+		if h.Host != nil {
+			target.props["host"] = string(*h.Host)
+		}
+		if h.Name != nil {
+			target.props["name"] = string(*h.Name)
+		}
+		if h.Status != nil {
+			target.props["status"] = string(*h.Status)
+		}
+		if groupIDsList[i] != nil {
+			target.groupIDs = groupIDsList[i]
+		}
+		ids[i] = id
+	}
+	return map[string]any{"hostids": ids}, nil
+}
+
+func (s *Server) hostDelete(params json.RawMessage) (any, error) {
+	ids, err := decodeIDs(params)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkHostsExist(ids); err != nil {
+		return nil, err
+	}
+	for _, id := range ids {
+		delete(s.hosts, id)
+	}
+	for _, m := range s.maintenances {
+		m.hostIDs = removeAll(m.hostIDs, ids)
+	}
+	// Triggers of deleted hosts are deleted too.
+	for id, t := range s.triggers {
+		if t.hostIDs = removeAll(t.hostIDs, ids); len(t.hostIDs) == 0 {
+			delete(s.triggers, id)
+		}
+	}
+	return map[string]any{"hostids": ids}, nil
+}
@@ -0,0 +1,181 @@
+package zabbixtest
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+type hostGroup struct {
+	props object
+}
+
+// AddHostGroup adds a host group and returns its ID.
+// It panics if a host group of the same name exists.
+func (s *Server) AddHostGroup(name string) (groupID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	ids, err := s.createHostGroups([]hostGroupParams{{Name: ptr(flexString(name))}})
+	if err != nil {
+		panic(fmt.Sprintf("zabbixtest: add host group: %v", err))
+	}
+	return ids[0]
+}
+
+type hostGroupParams struct {
+	GroupID *flexString `json:"groupid"`
+	Name    *flexString `json:"name"`
+}
+
+func (s *Server) hostGroupByName(name string) *hostGroup {
+	for _, g := range s.hostGroups {
+		if g.props["name"] == name {
+			return g
+		}
+	}
+	return nil
+}
+
+// hostGroupObjects returns host groups of groupIDs which exist.
+func (s *Server) hostGroupObjects(groupIDs []string) []object {
+	objs := []object{}
+	for _, id := range groupIDs {
+		if g, ok := s.hostGroups[id]; ok {
+			objs = append(objs, g.props)
+		}
+	}
+	sortObjects(objs, "groupid", nil, nil)
+	return objs
+}
+
+func (s *Server) checkHostGroupsExist(groupIDs []string) error {
+	for _, id := range groupIDs {
+		if _, ok := s.hostGroups[id]; !ok {
+			return errNoPermissions()
+		}
+	}
+	return nil
+}
+
+func (s *Server) hostGroupGet(params json.RawMessage) (any, error) {
+	p, err := decodeGetParams(params)
+	if err != nil {
+		return nil, err
+	}
+	objs := []object{}
+	for _, g := range s.hostGroups {
+		id := g.props["groupid"]
+		if !idsMatch(p.GroupIDs, id) || !idsMatchAny(p.HostIDs, s.hostIDsInGroup(id)) ||
+			!p.match(g.props) {
+			continue
+		}
+		objs = append(objs, g.props)
+	}
+	return p.result(objs, "groupid", func(obj object, res map[string]any) error {
+		return selectRelated(res, "hosts", p.SelectHosts, s.hostObjects(s.hostIDsInGroup(obj["groupid"])))
+	})
+}
+
+func (s *Server) hostGroupCreate(params json.RawMessage) (any, error) {
+	groups, err := decodeOneOrMany[hostGroupParams](params)
+	if err != nil {
+		return nil, err
+	}
+	ids, err := s.createHostGroups(groups)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]any{"groupids": ids}, nil
+}
+
+func (s *Server) createHostGroups(groups []hostGroupParams) ([]string, error) {
+	for i, g := range groups {
+		if g.Name == nil || *g.Name == "" {
+			return nil, errMissingParam(i, "name")
+		}
+		if s.hostGroupByName(string(*g.Name)) != nil {
+			return nil, errInvalidParams("Host group %q already exists.", *g.Name)
+		}
+	}
+	ids := make([]string, len(groups))
+	for i, g := range groups {
+		id := s.newID("hostgroup")
+		s.hostGroups[id] = &hostGroup{props: object{
+			"groupid":  id,
+			"name":     string(*g.Name),
+			"flags":    "0",
+			"internal": "0",
+		}}
+		ids[i] = id
+	}
+	return ids, nil
+}
+
+func (s *Server) hostGroupUpdate(params json.RawMessage) (any, error) {
+	groups, err := decodeOneOrMany[hostGroupParams](params)
+	if err != nil {
+		return nil, err
+	}
+	for i, g := range groups {
+		if g.GroupID == nil {
+			return nil, errMissingParam(i, "groupid")
+		}
+		if _, ok := s.hostGroups[string(*g.GroupID)]; !ok {
+			return nil, errNoPermissions()
+		}
+		if g.Name != nil {
+			if other := s.hostGroupByName(string(*g.Name)); other != nil &&
+				other.props["groupid"] != string(*g.GroupID) {
+				return nil, errInvalidParams("Host group %q already exists.", *g.Name)
+			}
+		}
+	}
+	ids := make([]string, len(groups))
+	for i, g := range groups {
+		id := string(*g.GroupID)
+		if g.Name != nil {
+			s.hostGroups[id].props["name"] = string(*g.Name)
+		}
+		ids[i] = id
+	}
+	return map[string]any{"groupids": ids}, nil
+}
+
+func (s *Server) hostGroupDelete(params json.RawMessage) (any, error) {
+	ids, err := decodeIDs(params)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkHostGroupsExist(ids); err != nil {
+		return nil, err
+	}
+	for _, h := range s.hosts {
+		if len(removeAll(h.groupIDs, ids)) == 0 {
+			return nil, errInvalidParams("Host %q cannot be without host group.", h.props["host"])
+		}
+	}
+	for _, id := range ids {
+		delete(s.hostGroups, id)
+	}
+	for _, h := range s.hosts {
+		h.groupIDs = removeAll(h.groupIDs, ids)
+	}
+	for _, m := range s.maintenances {
+		m.groupIDs = removeAll(m.groupIDs, ids)
+	}
+	return map[string]any{"groupids": ids}, nil
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+// removeAll returns a copy of ids without elements in removed.
+func removeAll(ids, removed []string) []string {
+	result := []string{}
+	for _, id := range ids {
+		if !stringList(removed).contains(id) {
+			result = append(result, id)
+		}
+	}
+	return result
+}
@@ -0,0 +1,379 @@
+package zabbixtest
+
+import (
+	"encoding/json"
+	"sort"
+	"strconv"
+)
+
+type maintenance struct {
+	props       object
+	groupIDs    []string
+	hostIDs     []string
+	timePeriods []object
+	tags        []object
+}
+
+type maintenanceParams struct {
+	MaintenanceID   *flexString         `json:"maintenanceid"`
+	Name            *flexString         `json:"name"`
+	ActiveSince     *flexString         `json:"active_since"`
+	ActiveTill      *flexString         `json:"active_till"`
+	Description     *flexString         `json:"description"`
+	MaintenanceType *flexString         `json:"maintenance_type"`
+	TagsEvalType    *flexString         `json:"tags_evaltype"`
+	Groups          *[]idObject         `json:"groups"`
+	Hosts           *[]idObject         `json:"hosts"`
+	TimePeriods     *[]timePeriodParams `json:"timeperiods"`
+	Tags            *[]tagParams        `json:"tags"`
+}
+
+type tagParams struct {
+	Tag      *flexString `json:"tag"`
+	Operator *flexString `json:"operator"`
+	Value    *flexString `json:"value"`
+}
+
+type timePeriodParams struct {
+	TimeperiodID   *flexString `json:"timeperiodid"`
+	TimeperiodType *flexString `json:"timeperiod_type"`
+	Every          *flexString `json:"every"`
+	Month          *flexString `json:"month"`
+	DayOfWeek      *flexString `json:"dayofweek"`
+	Day            *flexString `json:"day"`
+	StartTime      *flexString `json:"start_time"`
+	Period         *flexString `json:"period"`
+	StartDate      *flexString `json:"start_date"`
+}
+
+// hostMaintenance returns the maintenance in effect for h and the time when
+// it started. Recurring time periods are regarded as in effect during the
+// whole active period of the maintenance.
+func (s *Server) hostMaintenance(h *host) (m *maintenance, from int64) {
+	ids := make([]string, 0, len(s.maintenances))
+	for id := range s.maintenances {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return lessValue(ids[i], ids[j]) })
+
+	now := s.now().Unix()
+	for _, id := range ids {
+		m := s.maintenances[id]
+		if !stringList(m.hostIDs).contains(h.props["hostid"]) &&
+			!idsMatchAny((*stringList)(&m.groupIDs), h.groupIDs) {
+			continue
+		}
+		if from, ok := m.inEffectFrom(now); ok {
+			return m, from
+		}
+	}
+	return nil, 0
+}
+
+func (m *maintenance) inEffectFrom(now int64) (int64, bool) {
+	since := atoi(m.props["active_since"])
+	till := atoi(m.props["active_till"])
+	if now < since || now >= till {
+		return 0, false
+	}
+	for _, tp := range m.timePeriods {
+		if tp["timeperiod_type"] != "0" {
+			return since, true
+		}
+		start := atoi(tp["start_date"])
+		if start <= now && now < start+atoi(tp["period"]) {
+			if start < since {
+				start = since
+			}
+			return start, true
+		}
+	}
+	return 0, false
+}
+
+func atoi(s string) int64 {
+	i, _ := strconv.ParseInt(s, 10, 64)
+	return i
+}
+
+func (s *Server) maintenanceByName(name string) *maintenance {
+	for _, m := range s.maintenances {
+		if m.props["name"] == name {
+			return m
+		}
+	}
+	return nil
+}
+
+func (s *Server) maintenanceGet(params json.RawMessage) (any, error) {
+	p, err := decodeGetParams(params)
+	if err != nil {
+		return nil, err
+	}
+	objs := []object{}
+	for _, m := range s.maintenances {
+		if !idsMatch(p.MaintenanceIDs, m.props["maintenanceid"]) ||
+			!idsMatchAny(p.GroupIDs, m.groupIDs) || !idsMatchAny(p.HostIDs, m.hostIDs) ||
+			!p.match(m.props) {
+			continue
+		}
+		objs = append(objs, m.props)
+	}
+	return p.result(objs, "maintenanceid", func(obj object, res map[string]any) error {
+		m := s.maintenances[obj["maintenanceid"]]
+		if err := selectRelated(res, "groups", p.SelectGroups, s.hostGroupObjects(m.groupIDs)); err != nil {
+			return err
+		}
+		if err := selectRelated(res, "hosts", p.SelectHosts, s.hostObjects(m.hostIDs)); err != nil {
+			return err
+		}
+		if err := selectRelated(res, "timeperiods", p.SelectTimeperiods, m.timePeriods); err != nil {
+			return err
+		}
+		return selectRelated(res, "tags", p.SelectTags, m.tags)
+	})
+}
+
+func (s *Server) maintenanceCreate(params json.RawMessage) (any, error) {
+	ms, err := decodeOneOrMany[maintenanceParams](params)
+	if err != nil {
+		return nil, err
+	}
+	for i, m := range ms {
+		for _, required := range []struct {
+			name  string
+			value *flexString
+		}{
+			{"name", m.Name},
+			{"active_since", m.ActiveSince},
+			{"active_till", m.ActiveTill},
+		} {
+			if required.value == nil || *required.value == "" {
+				return nil, errMissingParam(i, required.name)
+			}
+		}
+		if m.TimePeriods == nil {
+			return nil, errMissingParam(i, "timeperiods")
+		}
+		if s.maintenanceByName(string(*m.Name)) != nil {
+			return nil, errInvalidParams("Maintenance %q already exists.", *m.Name)
+		}
+	}
+
+	ids := make([]string, len(ms))
+	updated := make([]*maintenance, len(ms))
+	for i, params := range ms {
+		m := &maintenance{props: object{
+			"maintenanceid":    s.newID("maintenance"),
+			"description":      "",
+			"maintenance_type": "0",
+			"tags_evaltype":    "0",
+		}}
+		if err := s.applyMaintenanceParams(m, i, params); err != nil {
+			return nil, err
+		}
+		updated[i] = m
+		ids[i] = m.props["maintenanceid"]
+	}
+	for _, m := range updated {
+		s.maintenances[m.props["maintenanceid"]] = m
+	}
+	return map[string]any{"maintenanceids": ids}, nil
+}
+
+func (s *Server) maintenanceUpdate(params json.RawMessage) (any, error) {
+	ms, err := decodeOneOrMany[maintenanceParams](params)
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]string, len(ms))
+	updated := make([]*maintenance, len(ms))
+	for i, params := range ms {
+		if params.MaintenanceID == nil {
+			return nil, errMissingParam(i, "maintenanceid")
+		}
+		orig, ok := s.maintenances[string(*params.MaintenanceID)]
+		if !ok {
+			return nil, errNoPermissions()
+		}
+		if params.Name != nil {
+			if other := s.maintenanceByName(string(*params.Name)); other != nil && other != orig {
+				return nil, errInvalidParams("Maintenance %q already exists.", *params.Name)
+			}
+		}
+		m := &maintenance{
+			props:       orig.props.clone(),
+			groupIDs:    orig.groupIDs,
+			hostIDs:     orig.hostIDs,
+			timePeriods: orig.timePeriods,
+			tags:        orig.tags,
+		}
+		if err := s.applyMaintenanceParams(m, i, params); err != nil {
+			return nil, err
+		}
+		updated[i] = m
+		ids[i] = m.props["maintenanceid"]
+	}
+	for _, m := range updated {
+		s.maintenances[m.props["maintenanceid"]] = m
+	}
+	return map[string]any{"maintenanceids": ids}, nil
+}
+
+// applyMaintenanceParams validates params and sets them to m.
+func (s *Server) applyMaintenanceParams(m *maintenance, index int, params maintenanceParams) error {
+	setProp(m.props, "name", params.Name)
+	setProp(m.props, "active_since", params.ActiveSince)
+	setProp(m.props, "active_till", params.ActiveTill)
+	setProp(m.props, "description", params.Description)
+	setProp(m.props, "maintenance_type", params.MaintenanceType)
+	setProp(m.props, "tags_evaltype", params.TagsEvalType)
+
+	if t := m.props["maintenance_type"]; t != "0" && t != "1" {
+		return errInvalidParams("Invalid parameter \"/%d/maintenance_type\": value must be one of 0, 1.", index+1)
+	}
+	if t := m.props["tags_evaltype"]; t != "0" && t != "2" {
+		return errInvalidParams("Invalid parameter \"/%d/tags_evaltype\": value must be one of 0, 2.", index+1)
+	}
+	if atoi(m.props["active_since"]) > atoi(m.props["active_till"]) {
+		return errInvalidParams("Maintenance \"active since\" value cannot be bigger than \"active till\".")
+	}
+
+	if params.Groups != nil {
+		groupIDs, err := toIDs(*params.Groups, "groupid")
+		if err != nil {
+			return err
+		}
+		if err := s.checkHostGroupsExist(groupIDs); err != nil {
+			return err
+		}
+		m.groupIDs = groupIDs
+	}
+	if params.Hosts != nil {
+		hostIDs, err := toIDs(*params.Hosts, "hostid")
+		if err != nil {
+			return err
+		}
+		if err := s.checkHostsExist(hostIDs); err != nil {
+			return err
+		}
+		m.hostIDs = hostIDs
+	}
+	if len(m.groupIDs) == 0 && len(m.hostIDs) == 0 {
+		return errInvalidParams("At least one host group or host must be selected.")
+	}
+
+	if params.TimePeriods != nil {
+		if len(*params.TimePeriods) == 0 {
+			return errInvalidParams("Invalid parameter \"/%d/timeperiods\": cannot be empty.", index+1)
+		}
+		timePeriods := make([]object, len(*params.TimePeriods))
+		for i, tp := range *params.TimePeriods {
+			obj, err := s.newTimePeriod(m, index, i, tp)
+			if err != nil {
+				return err
+			}
+			timePeriods[i] = obj
+		}
+		m.timePeriods = timePeriods
+	}
+
+	if params.Tags != nil {
+		tags := make([]object, len(*params.Tags))
+		for i, tp := range *params.Tags {
+			tag, err := newMaintenanceTag(index, i, tp)
+			if err != nil {
+				return err
+			}
+			tags[i] = tag
+		}
+		m.tags = tags
+	}
+	if len(m.tags) > 0 && m.props["maintenance_type"] != "0" {
+		return errInvalidParams("Invalid parameter \"/%d/tags\": should be empty.", index+1)
+	}
+	return nil
+}
+
+func newMaintenanceTag(index, i int, params tagParams) (object, error) {
+	if params.Tag == nil || *params.Tag == "" {
+		return nil, errInvalidParams("Invalid parameter \"/%d/tags/%d/tag\": cannot be empty.", index+1, i+1)
+	}
+	tag := object{
+		"tag":      string(*params.Tag),
+		"operator": "2",
+		"value":    "",
+	}
+	setProp(tag, "operator", params.Operator)
+	setProp(tag, "value", params.Value)
+	if op := tag["operator"]; op != "0" && op != "2" {
+		return nil, errInvalidParams("Invalid parameter \"/%d/tags/%d/operator\": value must be one of 0, 2.",
+			index+1, i+1)
+	}
+	return tag, nil
+}
+
+func (s *Server) newTimePeriod(m *maintenance, index, i int, params timePeriodParams) (object, error) {
+	tp := object{
+		"timeperiod_type": "0",
+		"every":           "1",
+		"month":           "0",
+		"dayofweek":       "0",
+		"day":             "0",
+		"start_time":      "0",
+		"period":          "3600",
+		"start_date":      strconv.FormatInt(s.now().Unix(), 10),
+	}
+	// Keep the ID of the existing time period.
+	if params.TimeperiodID != nil {
+		for _, orig := range m.timePeriods {
+			if orig["timeperiodid"] == string(*params.TimeperiodID) {
+				tp["timeperiodid"] = orig["timeperiodid"]
+			}
+		}
+	}
+	if tp["timeperiodid"] == "" {
+		tp["timeperiodid"] = s.newID("timeperiod")
+	}
+	setProp(tp, "timeperiod_type", params.TimeperiodType)
+	setProp(tp, "every", params.Every)
+	setProp(tp, "month", params.Month)
+	setProp(tp, "dayofweek", params.DayOfWeek)
+	setProp(tp, "day", params.Day)
+	setProp(tp, "start_time", params.StartTime)
+	setProp(tp, "period", params.Period)
+	setProp(tp, "start_date", params.StartDate)
+
+	switch tp["timeperiod_type"] {
+	case "0", "2", "3", "4":
+	default:
+		return nil, errInvalidParams("Invalid parameter \"/%d/timeperiods/%d/timeperiod_type\": value must be one of 0, 2, 3, 4.",
+			index+1, i+1)
+	}
+	if atoi(tp["period"]) < 300 {
+		return nil, errInvalidParams("Incorrect maintenance period (minimum 5 minutes).")
+	}
+	return tp, nil
+}
+
+func (s *Server) maintenanceDelete(params json.RawMessage) (any, error) {
+	ids, err := decodeIDs(params)
+	if err != nil {
+		return nil, err
+	}
+	for _, id := range ids {
+		if _, ok := s.maintenances[id]; !ok {
+			return nil, errNoPermissions()
+		}
+	}
+	for _, id := range ids {
+		delete(s.maintenances, id)
+	}
+	return map[string]any{"maintenanceids": ids}, nil
+}
+
+func setProp(props object, name string, value *flexString) {
+	if value != nil {
+		props[name] = string(*value)
+	}
+}
@@ -0,0 +1,338 @@
+package zabbixtest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// object is the scalar properties of an object.
+type object map[string]string
+
+func (o object) clone() object {
+	c := make(object, len(o))
+	for k, v := range o {
+		c[k] = v
+	}
+	return c
+}
+
+// flexString is a string which can be also encoded as a number in JSON.
+type flexString string
+
+func (s *flexString) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(data, []byte("null")) {
+		return nil
+	}
+	if len(data) > 0 && data[0] == '"' {
+		var v string
+		if err := json.Unmarshal(data, &v); err != nil {
+			return err
+		}
+		*s = flexString(v)
+		return nil
+	}
+	var n json.Number
+	if err := json.Unmarshal(data, &n); err != nil {
+		return fmt.Errorf("a character string or a number is expected: %s", data)
+	}
+	*s = flexString(n)
+	return nil
+}
+
+// stringList is a list of strings which can be also encoded as a single
+// string or a number in JSON.
+type stringList []string
+
+func (l *stringList) UnmarshalJSON(data []byte) error {
+	if len(data) > 0 && data[0] == '[' {
+		var ss []flexString
+		if err := json.Unmarshal(data, &ss); err != nil {
+			return err
+		}
+		*l = make(stringList, len(ss))
+		for i, s := range ss {
+			(*l)[i] = string(s)
+		}
+		return nil
+	}
+	if bytes.Equal(data, []byte("null")) {
+		return nil
+	}
+	var s flexString
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	*l = stringList{string(s)}
+	return nil
+}
+
+func (l stringList) contains(s string) bool {
+	for _, v := range l {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// idObject is an object which has only an ID like {"groupid": "2"}.
+type idObject map[string]flexString
+
+func toIDs(objs []idObject, idField string) ([]string, error) {
+	ids := make([]string, len(objs))
+	for i, obj := range objs {
+		id, ok := obj[idField]
+		if !ok {
+			return nil, errInvalidParams("Invalid parameter \"/%d\": the parameter %q is missing.", i+1, idField)
+		}
+		ids[i] = string(id)
+	}
+	return ids, nil
+}
+
+// decodeOneOrMany decodes params which is an object or an array of objects.
+func decodeOneOrMany[T any](params json.RawMessage) ([]T, error) {
+	params = bytes.TrimSpace(params)
+	if len(params) > 0 && params[0] == '[' {
+		var objs []T
+		if err := json.Unmarshal(params, &objs); err != nil {
+			return nil, errInvalidParams("Invalid parameter \"/\": %s.", err)
+		}
+		return objs, nil
+	}
+	var obj T
+	if err := json.Unmarshal(params, &obj); err != nil {
+		return nil, errInvalidParams("Invalid parameter \"/\": %s.", err)
+	}
+	return []T{obj}, nil
+}
+
+func decodeIDs(params json.RawMessage) ([]string, error) {
+	var ids []flexString
+	if err := json.Unmarshal(params, &ids); err != nil {
+		return nil, errInvalidParams("Invalid parameter \"/\": an array is expected.")
+	}
+	result := make([]string, len(ids))
+	for i, id := range ids {
+		result[i] = string(id)
+	}
+	return result, nil
+}
+
+// getParams is the common parameters of "*.get" methods.
+type getParams struct {
+	Output      json.RawMessage       `json:"output"`
+	CountOutput bool                  `json:"countOutput"`
+	Filter      map[string]stringList `json:"filter"`
+	Search      map[string]stringList `json:"search"`
+	SortField   stringList            `json:"sortfield"`
+	SortOrder   stringList            `json:"sortorder"`
+	Limit       flexString            `json:"limit"`
+
+	GroupIDs       *stringList `json:"groupids"`
+	HostIDs        *stringList `json:"hostids"`
+	ItemIDs        *stringList `json:"itemids"`
+	MaintenanceIDs *stringList `json:"maintenanceids"`
+	TriggerIDs     *stringList `json:"triggerids"`
+
+	SelectGroups      json.RawMessage `json:"selectGroups"`
+	SelectHosts       json.RawMessage `json:"selectHosts"`
+	SelectItems       json.RawMessage `json:"selectItems"`
+	SelectTimeperiods json.RawMessage `json:"selectTimeperiods"`
+	SelectTags        json.RawMessage `json:"selectTags"`
+}
+
+func decodeGetParams(params json.RawMessage) (*getParams, error) {
+	var p getParams
+	if len(bytes.TrimSpace(params)) > 0 {
+		if err := json.Unmarshal(params, &p); err != nil {
+			return nil, errInvalidParams("Invalid parameter \"/\": %s.", err)
+		}
+	}
+	return &p, nil
+}
+
+// idsMatch returns whether id is included in ids if ids is specified.
+func idsMatch(ids *stringList, id string) bool {
+	return ids == nil || ids.contains(id)
+}
+
+// idsMatchAny returns whether any of candidates is included in ids if ids is
+// specified.
+func idsMatchAny(ids *stringList, candidates []string) bool {
+	if ids == nil {
+		return true
+	}
+	for _, c := range candidates {
+		if ids.contains(c) {
+			return true
+		}
+	}
+	return false
+}
+
+// match returns whether props matches "filter" and "search".
+func (p *getParams) match(props object) bool {
+	for k, values := range p.Filter {
+		v, ok := props[k]
+		if len(values) > 0 && (!ok || !values.contains(v)) {
+			return false
+		}
+	}
+	for k, values := range p.Search {
+		v, ok := props[k]
+		if len(values) == 0 {
+			continue
+		}
+		if !ok {
+			return false
+		}
+		found := false
+		for _, s := range values {
+			if strings.Contains(strings.ToLower(v), strings.ToLower(s)) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// result sorts and limits objs, and returns them with the properties
+// specified with "output", or the count of them if "countOutput" is true.
+// relations is called for each object to add related objects like "hosts".
+func (p *getParams) result(objs []object, idField string, relations func(obj object, res map[string]any) error) (any, error) {
+	out, err := parseOutput(p.Output, "output")
+	if err != nil {
+		return nil, err
+	}
+	if p.CountOutput || out.count {
+		return strconv.Itoa(len(objs)), nil
+	}
+
+	sortObjects(objs, idField, p.SortField, p.SortOrder)
+	if p.Limit != "" {
+		limit, err := strconv.Atoi(string(p.Limit))
+		if err != nil || limit < 0 {
+			return nil, errInvalidParams("Invalid parameter \"/limit\": an integer is expected.")
+		}
+		if limit < len(objs) {
+			objs = objs[:limit]
+		}
+	}
+
+	results := make([]map[string]any, len(objs))
+	for i, obj := range objs {
+		results[i] = out.apply(obj)
+		if relations != nil {
+			if err := relations(obj, results[i]); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return results, nil
+}
+
+// selectRelated sets related objects to res[name] if sel is specified.
+func selectRelated(res map[string]any, name string, sel json.RawMessage, related []object) error {
+	if len(sel) == 0 || bytes.Equal(sel, []byte("null")) {
+		return nil
+	}
+	out, err := parseOutput(sel, "select"+strings.ToUpper(name[:1])+name[1:])
+	if err != nil {
+		return err
+	}
+	if out.count {
+		res[name] = strconv.Itoa(len(related))
+		return nil
+	}
+	objs := make([]map[string]any, len(related))
+	for i, obj := range related {
+		objs[i] = out.apply(obj)
+	}
+	res[name] = objs
+	return nil
+}
+
+type output struct {
+	extend bool
+	count  bool
+	fields []string
+}
+
+func parseOutput(raw json.RawMessage, param string) (output, error) {
+	if len(raw) == 0 || bytes.Equal(raw, []byte("null")) {
+		return output{extend: true}, nil
+	}
+	var s string
+	if err := json.Unmarshal(raw, &s); err == nil {
+		switch s {
+		case "extend":
+			return output{extend: true}, nil
+		case "count":
+			return output{count: true}, nil
+		default:
+			return output{fields: []string{s}}, nil
+		}
+	}
+	var fields []string
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		return output{}, errInvalidParams("Invalid parameter \"/%s\": value must be one of \"extend\", \"count\" or an array.", param)
+	}
+	return output{fields: fields}, nil
+}
+
+func (o output) apply(obj object) map[string]any {
+	res := make(map[string]any)
+	if o.extend {
+		for k, v := range obj {
+			res[k] = v
+		}
+		return res
+	}
+	for _, f := range o.fields {
+		if v, ok := obj[f]; ok {
+			res[f] = v
+		}
+	}
+	return res
+}
+
+func sortObjects(objs []object, idField string, sortFields, sortOrders stringList) {
+	sort.SliceStable(objs, func(i, j int) bool {
+		return lessValue(objs[i][idField], objs[j][idField])
+	})
+	for i := len(sortFields) - 1; i >= 0; i-- {
+		field := sortFields[i]
+		desc := false
+		if i < len(sortOrders) {
+			desc = strings.EqualFold(sortOrders[i], "DESC")
+		} else if len(sortOrders) == 1 {
+			desc = strings.EqualFold(sortOrders[0], "DESC")
+		}
+		sort.SliceStable(objs, func(a, b int) bool {
+			if desc {
+				return lessValue(objs[b][field], objs[a][field])
+			}
+			return lessValue(objs[a][field], objs[b][field])
+		})
+	}
+}
+
+// lessValue compares values as integers if both are integers, or as strings
+// otherwise.
+func lessValue(a, b string) bool {
+	ai, errA := strconv.ParseInt(a, 10, 64)
+	bi, errB := strconv.ParseInt(b, 10, 64)
+	if errA == nil && errB == nil {
+		return ai < bi
+	}
+	return a < b
+}
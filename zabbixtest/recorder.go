@@ -0,0 +1,365 @@
+package zabbixtest
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+)
+
+// RecorderMode is the mode of a Recorder.
+type RecorderMode int
+
+const (
+	// ModeReplay replays responses from a cassette file without sending
+	// requests to the server.
+	ModeReplay RecorderMode = iota
+	// ModeRecord sends requests to the server and records them and their
+	// responses. Call Recorder.Save to write them to a cassette file.
+	ModeRecord
+)
+
+// hiddenSecret is the value which secrets are replaced with in cassettes.
+const hiddenSecret = "(secret)"
+
+// Recorder is an http.RoundTripper which records JSON-RPC exchanges to a
+// cassette file and replays them. It can be used with zabbix.WithHTTPClient:
+//
+//	rec, err := zabbixtest.NewRecorder("testdata/host_get.json", zabbixtest.ModeReplay, nil)
+//	...
+//	client, err := zabbix.NewClient(zabbixURL,
+//		zabbix.WithHTTPClient(&http.Client{Transport: rec}))
+//
+// Requests are matched with recorded ones by methods and params, ignoring
+// request IDs. Secrets are not recorded: the password of "user.login", the
+// session ID and the API token in the params of "user.checkAuthentication",
+// the session IDs returned by them, the "auth" property and the
+// Authorization header. When identical requests are recorded more than once, the responses
+// are replayed in the recorded order.
+type Recorder struct {
+	path      string
+	mode      RecorderMode
+	transport http.RoundTripper
+
+	mu           sync.Mutex
+	interactions []Interaction
+	used         []bool
+}
+
+// Interaction is a pair of a request and a response recorded in a cassette.
+type Interaction struct {
+	// Request is the JSON-RPC request or batch request without IDs and
+	// secrets.
+	Request json.RawMessage `json:"request"`
+
+	StatusCode  int    `json:"status_code"`
+	ContentType string `json:"content_type,omitempty"`
+	// Response is the response body if it is JSON. The IDs in the response
+	// are replaced with the indexes of the requests in the batch request,
+	// or 0 for a single request.
+	Response json.RawMessage `json:"response,omitempty"`
+	// RawResponse is the response body if it is not JSON, for example an
+	// error page of a reverse proxy.
+	RawResponse string `json:"raw_response,omitempty"`
+}
+
+type cassette struct {
+	Interactions []Interaction `json:"interactions"`
+}
+
+// NewRecorder creates a Recorder. In ModeReplay, the cassette file at path
+// is read. In ModeRecord, requests are sent with transport, or
+// http.DefaultTransport if transport is nil.
+func NewRecorder(path string, mode RecorderMode, transport http.RoundTripper) (*Recorder, error) {
+	r := &Recorder{path: path, mode: mode, transport: transport}
+	switch mode {
+	case ModeReplay:
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		var c cassette
+		if err := json.Unmarshal(data, &c); err != nil {
+			return nil, fmt.Errorf("zabbixtest: parse cassette %s: %w", path, err)
+		}
+		// Requests are indented in the file.
+		for i, in := range c.Interactions {
+			var buf bytes.Buffer
+			if err := json.Compact(&buf, in.Request); err != nil {
+				return nil, fmt.Errorf("zabbixtest: parse cassette %s: %w", path, err)
+			}
+			c.Interactions[i].Request = buf.Bytes()
+		}
+		r.interactions = c.Interactions
+		r.used = make([]bool, len(c.Interactions))
+	case ModeRecord:
+		if r.transport == nil {
+			r.transport = http.DefaultTransport
+		}
+	default:
+		return nil, fmt.Errorf("zabbixtest: invalid recorder mode: %d", mode)
+	}
+	return r, nil
+}
+
+// Interactions returns the recorded or loaded interactions.
+func (r *Recorder) Interactions() []Interaction {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Interaction(nil), r.interactions...)
+}
+
+// Save writes the recorded interactions to the cassette file.
+func (r *Recorder) Save() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	data, err := json.MarshalIndent(cassette{Interactions: r.interactions}, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(r.path, append(data, '\n'), 0o644)
+}
+
+// RoundTrip implements http.RoundTripper.
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	var reqBody []byte
+	if req.Body != nil {
+		var err error
+		reqBody, err = io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+	normalized, ids, err := normalizeRequest(reqBody)
+	if err != nil {
+		return nil, fmt.Errorf("zabbixtest: normalize request: %w", err)
+	}
+
+	if r.mode == ModeReplay {
+		in, err := r.find(normalized)
+		if err != nil {
+			return nil, err
+		}
+		return in.response(req, ids)
+	}
+
+	outReq := req.Clone(req.Context())
+	outReq.Body = io.NopCloser(bytes.NewReader(reqBody))
+	outReq.ContentLength = int64(len(reqBody))
+	resp, err := r.transport.RoundTrip(outReq)
+	if err != nil {
+		return nil, err
+	}
+	respBody, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+
+	in := Interaction{
+		Request:     normalized,
+		StatusCode:  resp.StatusCode,
+		ContentType: resp.Header.Get("Content-Type"),
+	}
+	if recorded, err := normalizeResponse(respBody, ids, returnsSession(normalized)); err == nil {
+		in.Response = recorded
+	} else {
+		in.RawResponse = string(respBody)
+	}
+	r.mu.Lock()
+	r.interactions = append(r.interactions, in)
+	r.mu.Unlock()
+	return resp, nil
+}
+
+// find returns the first unused interaction for the request, or the last
+// used one if all of them are used.
+func (r *Recorder) find(normalized json.RawMessage) (*Interaction, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	last := -1
+	for i, in := range r.interactions {
+		if !bytes.Equal(in.Request, normalized) {
+			continue
+		}
+		if !r.used[i] {
+			r.used[i] = true
+			return &r.interactions[i], nil
+		}
+		last = i
+	}
+	if last == -1 {
+		return nil, fmt.Errorf("zabbixtest: no recorded interaction for request: %s", normalized)
+	}
+	return &r.interactions[last], nil
+}
+
+// response returns the recorded response with IDs replaced with ones of the
+// request.
+func (in *Interaction) response(req *http.Request, ids []json.RawMessage) (*http.Response, error) {
+	body := []byte(in.RawResponse)
+	if in.Response != nil {
+		var err error
+		body, err = restoreResponseIDs(in.Response, ids)
+		if err != nil {
+			return nil, fmt.Errorf("zabbixtest: restore response IDs: %w", err)
+		}
+	}
+	header := make(http.Header)
+	if in.ContentType != "" {
+		header.Set("Content-Type", in.ContentType)
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", in.StatusCode, http.StatusText(in.StatusCode)),
+		StatusCode:    in.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}, nil
+}
+
+// normalizeRequest removes IDs and secrets from a request body and returns
+// it in the canonical form with the removed IDs.
+func normalizeRequest(body []byte) (normalized json.RawMessage, ids []json.RawMessage, err error) {
+	batch := len(bytes.TrimSpace(body)) > 0 && bytes.TrimSpace(body)[0] == '['
+	var reqs []map[string]any
+	if batch {
+		err = json.Unmarshal(body, &reqs)
+	} else {
+		var req map[string]any
+		err = json.Unmarshal(body, &req)
+		reqs = []map[string]any{req}
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ids = make([]json.RawMessage, len(reqs))
+	for i, req := range reqs {
+		id, err := json.Marshal(req["id"])
+		if err != nil {
+			return nil, nil, err
+		}
+		ids[i] = id
+		delete(req, "id")
+		delete(req, "auth")
+		if params, ok := req["params"].(map[string]any); ok {
+			switch req["method"] {
+			case "user.login":
+				hideSecrets(params, "password")
+			case "user.checkAuthentication":
+				hideSecrets(params, "sessionid", "token")
+			}
+		}
+	}
+
+	// json.Marshal sorts keys of maps, so the result is canonical.
+	if batch {
+		normalized, err = json.Marshal(reqs)
+	} else {
+		normalized, err = json.Marshal(reqs[0])
+	}
+	return normalized, ids, err
+}
+
+// hideSecrets replaces the values of keys in obj with hiddenSecret.
+func hideSecrets(obj map[string]any, keys ...string) {
+	for _, k := range keys {
+		if _, ok := obj[k]; ok {
+			obj[k] = hiddenSecret
+		}
+	}
+}
+
+// returnsSession returns whether the result of the request is a session ID
+// or user data with a session ID.
+func returnsSession(normalized json.RawMessage) bool {
+	var req struct {
+		Method string `json:"method"`
+	}
+	return json.Unmarshal(normalized, &req) == nil &&
+		(req.Method == "user.login" || req.Method == "user.checkAuthentication")
+}
+
+// normalizeResponse replaces IDs in a response body with the indexes of ids
+// and hides the session ID if session is true.
+func normalizeResponse(body []byte, ids []json.RawMessage, session bool) (json.RawMessage, error) {
+	return mapResponses(body, func(res map[string]any) error {
+		id, err := json.Marshal(res["id"])
+		if err != nil {
+			return err
+		}
+		res["id"] = 0
+		for i, reqID := range ids {
+			if bytes.Equal(id, reqID) {
+				res["id"] = i
+				break
+			}
+		}
+		if !session {
+			return nil
+		}
+		switch result := res["result"].(type) {
+		case string:
+			res["result"] = hiddenSecret
+		case map[string]any:
+			hideSecrets(result, "sessionid")
+		}
+		return nil
+	})
+}
+
+// restoreResponseIDs replaces indexes in a recorded response body with ids.
+func restoreResponseIDs(recorded json.RawMessage, ids []json.RawMessage) ([]byte, error) {
+	return mapResponses(recorded, func(res map[string]any) error {
+		i, ok := res["id"].(float64)
+		if !ok || int(i) < 0 || int(i) >= len(ids) {
+			return errors.New("invalid index in recorded response")
+		}
+		res["id"] = ids[int(i)]
+		return nil
+	})
+}
+
+// mapResponses calls fn for each response object in body, which is a single
+// response or a batch response, and returns the modified body.
+func mapResponses(body []byte, fn func(res map[string]any) error) ([]byte, error) {
+	trimmed := bytes.TrimSpace(body)
+	if strings.HasPrefix(string(trimmed), "[") {
+		var resps []map[string]any
+		if err := json.Unmarshal(trimmed, &resps); err != nil {
+			return nil, err
+		}
+		for _, res := range resps {
+			if res == nil {
+				return nil, errors.New("response is not an object")
+			}
+			if err := fn(res); err != nil {
+				return nil, err
+			}
+		}
+		return json.Marshal(resps)
+	}
+	var res map[string]any
+	if err := json.Unmarshal(trimmed, &res); err != nil {
+		return nil, err
+	}
+	if res == nil {
+		return nil, errors.New("response is not an object")
+	}
+	if err := fn(res); err != nil {
+		return nil, err
+	}
+	return json.Marshal(res)
+}
@@ -0,0 +1,170 @@
+package zabbixtest_test
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hnakamur/go-zabbix"
+	"github.com/hnakamur/go-zabbix/host"
+	"github.com/hnakamur/go-zabbix/trigger"
+	"github.com/hnakamur/go-zabbix/zabbixtest"
+)
+
+func TestRecorder(t *testing.T) {
+	const password = "password1"
+	path := filepath.Join(t.TempDir(), "cassette.json")
+	ctx := context.Background()
+
+	run := func(t *testing.T, zabbixURL string, rec *zabbixtest.Recorder) (string, []string) {
+		t.Helper()
+		client, err := zabbix.NewClient(zabbixURL,
+			zabbix.WithHTTPClient(&http.Client{Transport: rec}))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := client.Login(ctx, "user1", password); err != nil {
+			t.Fatal(err)
+		}
+		hosts, err := host.GetByNamesFullMatch(ctx, client, []string{"server1"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids, err := trigger.SetStatus(ctx, client, []string{"20001", "20002"}, trigger.StatusDisabled)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return hosts[0].HostID, ids
+	}
+
+	s := zabbixtest.NewServer(zabbixtest.WithUser("user1", password))
+	groupID := s.AddHostGroup("Linux servers")
+	s.AddHost("server1", groupID)
+	s.AddHost("server2", groupID)
+	s.AddTrigger("trigger1", "last(/server1/agent.ping)=0")
+	s.AddTrigger("trigger2", "last(/server2/agent.ping)=0")
+
+	rec, err := zabbixtest.NewRecorder(path, zabbixtest.ModeRecord, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantHostID, wantIDs := run(t, s.URL, rec)
+	if err := rec.Save(); err != nil {
+		t.Fatal(err)
+	}
+	s.Close()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), password) {
+		t.Errorf("cassette contains the password: %s", data)
+	}
+
+	rec, err = zabbixtest.NewRecorder(path, zabbixtest.ModeReplay, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The server is closed, so responses must come from the cassette.
+	gotHostID, gotIDs := run(t, s.URL, rec)
+	if gotHostID != wantHostID {
+		t.Errorf("host ID mismatch, got=%s, want=%s", gotHostID, wantHostID)
+	}
+	if strings.Join(gotIDs, ",") != strings.Join(wantIDs, ",") {
+		t.Errorf("trigger IDs mismatch, got=%v, want=%v", gotIDs, wantIDs)
+	}
+
+	client, err := zabbix.NewClient(s.URL, zabbix.WithHTTPClient(&http.Client{Transport: rec}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := host.GetByNamesFullMatch(ctx, client, []string{"server2"}); err == nil {
+		t.Error("want error for request not recorded but got no error")
+	}
+}
+
+func TestRecorderCheckAuthentication(t *testing.T) {
+	const password = "password1"
+	ctx := context.Background()
+
+	testCases := []struct {
+		name string
+		// login logs in with client and returns the secrets which must not
+		// be recorded.
+		login func(t *testing.T, client *zabbix.Client) []string
+		opts  []zabbix.ClientOpt
+	}{
+		{
+			name: "session",
+			login: func(t *testing.T, client *zabbix.Client) []string {
+				if err := client.Login(ctx, "user1", password); err != nil {
+					t.Fatal(err)
+				}
+				return []string{password, client.SessionID()}
+			},
+		},
+		{
+			name: "token",
+			login: func(t *testing.T, client *zabbix.Client) []string {
+				return []string{"token1"}
+			},
+			opts: []zabbix.ClientOpt{zabbix.WithAPIToken("token1")},
+		},
+	}
+	for _, c := range testCases {
+		t.Run(c.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "cassette.json")
+			s := zabbixtest.NewServer(zabbixtest.WithUser("user1", password),
+				zabbixtest.WithAPIToken("token1"), zabbixtest.WithAPIVersion("6.4.0"))
+			rec, err := zabbixtest.NewRecorder(path, zabbixtest.ModeRecord, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			client, err := zabbix.NewClient(s.URL, append(c.opts,
+				zabbix.WithHTTPClient(&http.Client{Transport: rec}))...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			secrets := c.login(t, client)
+			if _, err := client.CheckAuthentication(ctx); err != nil {
+				t.Fatal(err)
+			}
+			if err := rec.Save(); err != nil {
+				t.Fatal(err)
+			}
+			s.Close()
+
+			data, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, secret := range secrets {
+				if strings.Contains(string(data), secret) {
+					t.Errorf("cassette contains secret %q: %s", secret, data)
+				}
+			}
+
+			// The secrets may differ in replays.
+			rec, err = zabbixtest.NewRecorder(path, zabbixtest.ModeReplay, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			opts := []zabbix.ClientOpt{zabbix.WithHTTPClient(&http.Client{Transport: rec})}
+			if c.opts != nil {
+				opts = append(opts, zabbix.WithAPIToken("token2"))
+			}
+			client, err = zabbix.NewClient(s.URL, opts...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			c.login(t, client)
+			if _, err := client.CheckAuthentication(ctx); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
@@ -0,0 +1,322 @@
+// Package zabbixtest provides a fake Zabbix JSON-RPC server for tests.
+//
+// The server implements "apiinfo.version", "user.login", "user.logout",
+// "user.checkAuthentication" and in-memory CRUD of hosts, host groups, maintenances and triggers. Only the
+// parameters used commonly are supported, and errors are returned with the
+// same codes and messages as Zabbix 6.0.
+//
+//	s := zabbixtest.NewServer()
+//	defer s.Close()
+//	groupID := s.AddHostGroup("Linux servers")
+//	s.AddHost("server1", groupID)
+//
+//	client, err := zabbix.NewClient(s.URL)
+//	...
+//	err = client.Login(ctx, zabbixtest.DefaultUsername, zabbixtest.DefaultPassword)
+//
+// The package also provides Recorder, which records exchanges with a real
+// Zabbix server and replays them.
+package zabbixtest
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultAPIVersion is the API version returned by "apiinfo.version"
+	// unless WithAPIVersion is specified.
+	DefaultAPIVersion = "6.0.0"
+	// DefaultUsername and DefaultPassword are the credentials of the user
+	// who can log in unless WithUser is specified.
+	DefaultUsername = "Admin"
+	DefaultPassword = "zabbix"
+)
+
+const jsonrpcVersion = "2.0"
+
+// Server is a fake Zabbix server. Its URL can be passed to zabbix.NewClient.
+type Server struct {
+	*httptest.Server
+
+	mu         sync.Mutex
+	apiVersion string
+	users      map[string]string
+	apiTokens  map[string]struct{}
+	// sessions maps session IDs to usernames.
+	sessions map[string]string
+	now      func() time.Time
+	requests []Request
+	lastIDs  map[string]int
+	// auth is the session ID or the API token of the request being handled.
+	auth string
+
+	hostGroups   map[string]*hostGroup
+	hosts        map[string]*host
+	maintenances map[string]*maintenance
+	triggers     map[string]*trigger
+}
+
+// Request is a JSON-RPC request received by the Server.
+type Request struct {
+	Method string
+	Params json.RawMessage
+	// Auth is the "auth" property or the bearer token in the Authorization
+	// header.
+	Auth string
+}
+
+// Option is an option for NewServer.
+type Option func(s *Server)
+
+// WithAPIVersion sets the API version returned by "apiinfo.version".
+func WithAPIVersion(version string) Option {
+	return func(s *Server) {
+		s.apiVersion = version
+	}
+}
+
+// WithUser adds a user who can log in. If WithUser is not specified, a user
+// with DefaultUsername and DefaultPassword is added.
+func WithUser(username, password string) Option {
+	return func(s *Server) {
+		s.users[username] = password
+	}
+}
+
+// WithAPIToken adds an API token which can be used without logging in.
+func WithAPIToken(token string) Option {
+	return func(s *Server) {
+		s.apiTokens[token] = struct{}{}
+	}
+}
+
+// WithClock sets the function to get the current time, which is used to
+// decide whether maintenances are in effect. The default is time.Now.
+func WithClock(now func() time.Time) Option {
+	return func(s *Server) {
+		s.now = now
+	}
+}
+
+// NewServer starts and returns a new Server. The caller should call Close
+// when finished, to shut it down.
+func NewServer(opts ...Option) *Server {
+	s := &Server{
+		apiVersion:   DefaultAPIVersion,
+		users:        make(map[string]string),
+		apiTokens:    make(map[string]struct{}),
+		sessions:     make(map[string]string),
+		now:          time.Now,
+		lastIDs:      make(map[string]int),
+		hostGroups:   make(map[string]*hostGroup),
+		hosts:        make(map[string]*host),
+		maintenances: make(map[string]*maintenance),
+		triggers:     make(map[string]*trigger),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	if len(s.users) == 0 {
+		s.users[DefaultUsername] = DefaultPassword
+	}
+	s.Server = httptest.NewServer(s)
+	return s
+}
+
+// ExpireSessions terminates all sessions created by "user.login", so that
+// following requests with them fail with the "Session terminated" error.
+func (s *Server) ExpireSessions() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sessions = make(map[string]string)
+}
+
+// Requests returns the requests received by the Server so far.
+func (s *Server) Requests() []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Request(nil), s.requests...)
+}
+
+type handlerFunc func(s *Server, params json.RawMessage) (any, error)
+
+var handlers = map[string]handlerFunc{
+	"apiinfo.version":          (*Server).apiInfoVersion,
+	"user.login":               (*Server).userLogin,
+	"user.logout":              (*Server).userLogout,
+	"user.checkAuthentication": (*Server).userCheckAuthentication,
+	"hostgroup.get":            (*Server).hostGroupGet,
+	"hostgroup.create":         (*Server).hostGroupCreate,
+	"hostgroup.update":         (*Server).hostGroupUpdate,
+	"hostgroup.delete":         (*Server).hostGroupDelete,
+	"host.get":                 (*Server).hostGet,
+	"host.create":              (*Server).hostCreate,
+	"host.update":              (*Server).hostUpdate,
+	"host.delete":              (*Server).hostDelete,
+	"maintenance.get":          (*Server).maintenanceGet,
+	"maintenance.create":       (*Server).maintenanceCreate,
+	"maintenance.update":       (*Server).maintenanceUpdate,
+	"maintenance.delete":       (*Server).maintenanceDelete,
+	"trigger.get":              (*Server).triggerGet,
+	"trigger.create":           (*Server).triggerCreate,
+	"trigger.update":           (*Server).triggerUpdate,
+	"trigger.delete":           (*Server).triggerDelete,
+}
+
+// methodsWithoutAuth is the methods which must be called without auth.
+var methodsWithoutAuth = map[string]bool{
+	"apiinfo.version":          true,
+	"user.login":               true,
+	"user.checkAuthentication": true,
+}
+
+type request struct {
+	Jsonrpc string          `json:"jsonrpc"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params"`
+	ID      any             `json:"id"`
+	Auth    *string         `json:"auth"`
+}
+
+type response struct {
+	Jsonrpc string    `json:"jsonrpc"`
+	Result  any       `json:"result,omitempty"`
+	Error   *apiError `json:"error,omitempty"`
+	ID      any       `json:"id"`
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !strings.HasSuffix(r.URL.Path, "/api_jsonrpc.php") {
+		http.NotFound(w, r)
+		return
+	}
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	var bearer string
+	if v, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer "); ok {
+		bearer = v
+	}
+
+	s.mu.Lock()
+	var res any
+	if trimmed := bytes.TrimSpace(body); len(trimmed) > 0 && trimmed[0] == '[' {
+		var reqs []json.RawMessage
+		if err := json.Unmarshal(body, &reqs); err != nil {
+			res = errorResponse(nil, errParse())
+		} else {
+			resps := make([]response, len(reqs))
+			for i, req := range reqs {
+				resps[i] = s.handle(req, bearer)
+			}
+			res = resps
+		}
+	} else {
+		res = s.handle(body, bearer)
+	}
+	s.mu.Unlock()
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(res)
+}
+
+func (s *Server) handle(data []byte, bearer string) response {
+	var req request
+	if err := json.Unmarshal(data, &req); err != nil {
+		return errorResponse(nil, errParse())
+	}
+	if req.Jsonrpc != jsonrpcVersion || req.Method == "" {
+		return errorResponse(req.ID, &apiError{Code: -32600,
+			Message: "Invalid Request.", Data: "JSON-rpc version is not specified or method is empty."})
+	}
+	auth := bearer
+	if req.Auth != nil {
+		auth = *req.Auth
+	}
+	s.requests = append(s.requests, Request{Method: req.Method, Params: req.Params, Auth: auth})
+
+	handler, ok := handlers[req.Method]
+	if !ok {
+		return errorResponse(req.ID, errMethodNotFound(req.Method))
+	}
+	if methodsWithoutAuth[req.Method] {
+		if auth != "" {
+			return errorResponse(req.ID, errInvalidParams(
+				"The %q method must be called without the \"auth\" parameter.", req.Method))
+		}
+	} else if err := s.checkAuth(auth); err != nil {
+		return errorResponse(req.ID, err)
+	}
+
+	s.auth = auth
+	result, err := handler(s, req.Params)
+	if err != nil {
+		apiErr, ok := err.(*apiError)
+		if !ok {
+			apiErr = errInvalidParams("%s", err)
+		}
+		return errorResponse(req.ID, apiErr)
+	}
+	return response{Jsonrpc: jsonrpcVersion, Result: result, ID: req.ID}
+}
+
+func errorResponse(id any, err *apiError) response {
+	return response{Jsonrpc: jsonrpcVersion, Error: err, ID: id}
+}
+
+func (s *Server) checkAuth(auth string) *apiError {
+	if auth == "" {
+		return errNotAuthorised()
+	}
+	if _, ok := s.sessions[auth]; ok {
+		return nil
+	}
+	if _, ok := s.apiTokens[auth]; ok {
+		return nil
+	}
+	return errSessionTerminated()
+}
+
+// newID returns a new ID for objects of kind. IDs of each kind start from
+// different numbers so that using an ID of another kind is detected.
+func (s *Server) newID(kind string) string {
+	if _, ok := s.lastIDs[kind]; !ok {
+		s.lastIDs[kind] = idBases[kind]
+	}
+	s.lastIDs[kind]++
+	return strconv.Itoa(s.lastIDs[kind])
+}
+
+var idBases = map[string]int{
+	"hostgroup":   0,
+	"host":        10000,
+	"maintenance": 0,
+	"timeperiod":  100,
+	"trigger":     20000,
+}
+
+func newSessionID() string {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		panic(fmt.Sprintf("zabbixtest: generate session ID: %v", err))
+	}
+	return hex.EncodeToString(b[:])
+}
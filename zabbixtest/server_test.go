@@ -0,0 +1,251 @@
+package zabbixtest_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/hnakamur/go-zabbix"
+	"github.com/hnakamur/go-zabbix/host"
+	"github.com/hnakamur/go-zabbix/hostgroup"
+	"github.com/hnakamur/go-zabbix/maintenance"
+	"github.com/hnakamur/go-zabbix/trigger"
+	"github.com/hnakamur/go-zabbix/zabbixtest"
+)
+
+func newLoggedInClient(t *testing.T, s *zabbixtest.Server, opts ...zabbix.ClientOpt) *zabbix.Client {
+	t.Helper()
+	client, err := zabbix.NewClient(s.URL, opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := client.Login(context.Background(), zabbixtest.DefaultUsername, zabbixtest.DefaultPassword); err != nil {
+		t.Fatal(err)
+	}
+	return client
+}
+
+func TestServerLogin(t *testing.T) {
+	s := zabbixtest.NewServer(zabbixtest.WithAPIToken("token1"))
+	defer s.Close()
+	ctx := context.Background()
+
+	client, err := zabbix.NewClient(s.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = client.Login(ctx, zabbixtest.DefaultUsername, "wrong")
+	if got, want := zabbix.GetErrorCode(err), zabbix.ErrorCodeApplication; got != want {
+		t.Errorf("error code mismatch for wrong password, got=%v, want=%v", got, want)
+	}
+
+	_, err = hostgroup.GetNestedByAncestorNames(ctx, client, nil)
+	var apiErr *zabbix.APIError
+	if !errors.As(err, &apiErr) || apiErr.Data != "Not authorised." {
+		t.Errorf("error mismatch without auth, got=%v", err)
+	}
+
+	client = newLoggedInClient(t, s)
+	if _, err := hostgroup.GetNestedByAncestorNames(ctx, client, nil); err != nil {
+		t.Errorf("call after login: %v", err)
+	}
+
+	tokenClient, err := zabbix.NewClient(s.URL, zabbix.WithAPIToken("token1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := hostgroup.GetNestedByAncestorNames(ctx, tokenClient, nil); err != nil {
+		t.Errorf("call with API token: %v", err)
+	}
+
+	user, err := client.CheckAuthentication(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := user.Username, zabbixtest.DefaultUsername; got != want {
+		t.Errorf("username mismatch, got=%q, want=%q", got, want)
+	}
+
+	logoutClient := newLoggedInClient(t, s)
+	if err := logoutClient.Logout(ctx); err != nil {
+		t.Fatal(err)
+	}
+	reqs := s.Requests()
+	// Reuse the terminated session ID as if it were an API token.
+	staleClient, err := zabbix.NewClient(s.URL, zabbix.WithAPIToken(reqs[len(reqs)-1].Auth))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := hostgroup.GetNestedByAncestorNames(ctx, staleClient, nil); !zabbix.IsSessionExpired(err) {
+		t.Errorf("error mismatch after logout, got=%v", err)
+	}
+
+	s.ExpireSessions()
+	_, err = hostgroup.GetNestedByAncestorNames(ctx, client, nil)
+	if !errors.As(err, &apiErr) || apiErr.Data != "Session terminated, re-login, please." {
+		t.Errorf("error mismatch after sessions expired, got=%v", err)
+	}
+
+	err = client.Call(ctx, "host.nosuchmethod", []string{}, nil)
+	if got, want := zabbix.GetErrorCode(err), zabbix.ErrorCodeMethodNotFound; got != want {
+		t.Errorf("error code mismatch for unknown method, got=%v, want=%v", got, want)
+	}
+}
+
+func TestServerMaintenance(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	s := zabbixtest.NewServer(zabbixtest.WithClock(func() time.Time { return now }))
+	defer s.Close()
+	ctx := context.Background()
+	client := newLoggedInClient(t, s)
+
+	groupID := s.AddHostGroup("Linux servers")
+	s.AddHost("server1", groupID)
+	s.AddHost("server2", groupID)
+
+	hosts, err := host.GetByNamesFullMatch(ctx, client, []string{"server1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := maintenance.Maintenance{
+		Name:            "test",
+		ActiveSince:     now.Add(-time.Hour),
+		ActiveTill:      now.Add(time.Hour),
+		MaintenanceType: host.MaintenanceTypeNoData,
+		Hosts:           hosts,
+		TimePeriods: []maintenance.TimePeriod{{
+			Period:         2 * time.Hour,
+			TimeperiodType: maintenance.TimeperiodTypeOnetimeOnly,
+			StartDate:      now.Add(-time.Hour),
+		}},
+	}
+	if err := maintenance.Create(ctx, client, &m); err != nil {
+		t.Fatal(err)
+	}
+	err = maintenance.Create(ctx, client, &m)
+	if got, want := zabbix.GetErrorCode(err), zabbix.ErrorCodeInvalidParams; got != want {
+		t.Errorf("error code mismatch for duplicated name, got=%v, want=%v", got, want)
+	}
+
+	got, err := maintenance.GetByNameFullMatch(ctx, client, "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.MaintenanceID != m.MaintenanceID || len(got.Hosts) != 1 ||
+		got.Hosts[0].Name != "server1" || len(got.TimePeriods) != 1 ||
+		got.TimePeriods[0].Period != 2*time.Hour {
+		t.Errorf("maintenance mismatch, got=%+v", got)
+	}
+
+	hosts, err = host.GetByGroupIDs(ctx, client, []string{groupID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, h := range hosts {
+		want := host.MaintenanceStatusNoMaintenance
+		if h.Name == "server1" {
+			want = host.MaintenanceStatusInEffect
+		}
+		if h.MaintenanceStatus != want {
+			t.Errorf("maintenance status mismatch for %s, got=%s, want=%s", h.Name, h.MaintenanceStatus, want)
+		}
+	}
+
+	// Problem tags can be set only for maintenances with data collection.
+	m.Tags = []maintenance.ProblemTag{
+		{Tag: "service", Operator: maintenance.TagOperatorEquals, Value: "web"},
+	}
+	err = maintenance.Update(ctx, client, &m)
+	if got, want := zabbix.GetErrorCode(err), zabbix.ErrorCodeInvalidParams; got != want {
+		t.Errorf("error code mismatch for tags with no data, got=%v, want=%v", got, want)
+	}
+	m.MaintenanceType = host.MaintenanceTypeWithData
+	m.TagsEvalType = "1"
+	err = maintenance.Update(ctx, client, &m)
+	if got, want := zabbix.GetErrorCode(err), zabbix.ErrorCodeInvalidParams; got != want {
+		t.Errorf("error code mismatch for invalid tags_evaltype, got=%v, want=%v", got, want)
+	}
+	m.TagsEvalType = maintenance.TagsEvalTypeOr
+	if err := maintenance.Update(ctx, client, &m); err != nil {
+		t.Fatal(err)
+	}
+	got, err = maintenance.GetByID(ctx, client, m.MaintenanceID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got.Tags, m.Tags) || got.TagsEvalType != maintenance.TagsEvalTypeOr {
+		t.Errorf("tags mismatch, got=%+v (%s), want=%+v (%s)", got.Tags, got.TagsEvalType,
+			m.Tags, maintenance.TagsEvalTypeOr)
+	}
+
+	deletedIDs, err := maintenance.DeleteByIDs(ctx, client, []string{m.MaintenanceID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(deletedIDs) != 1 || deletedIDs[0] != m.MaintenanceID {
+		t.Errorf("deleted IDs mismatch, got=%v, want=[%s]", deletedIDs, m.MaintenanceID)
+	}
+	_, err = maintenance.DeleteByIDs(ctx, client, []string{m.MaintenanceID})
+	if got, want := zabbix.GetErrorCode(err), zabbix.ErrorCodeApplication; got != want {
+		t.Errorf("error code mismatch for deleted maintenance, got=%v, want=%v", got, want)
+	}
+}
+
+func TestServerTrigger(t *testing.T) {
+	s := zabbixtest.NewServer()
+	defer s.Close()
+	ctx := context.Background()
+	client := newLoggedInClient(t, s)
+
+	groupID := s.AddHostGroup("Linux servers")
+	hostID := s.AddHost("server1", groupID)
+	triggerID := s.AddTrigger("High CPU load", "last(/server1/system.cpu.load)>5")
+
+	ids, err := trigger.SetStatus(ctx, client, []string{triggerID}, trigger.StatusDisabled)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ids) != 1 || ids[0] != triggerID {
+		t.Errorf("updated IDs mismatch, got=%v, want=[%s]", ids, triggerID)
+	}
+
+	triggers, err := trigger.Get(ctx, client, trigger.GetOptions{HostIDs: []string{hostID}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(triggers) != 1 || triggers[0].Status != trigger.StatusDisabled ||
+		len(triggers[0].Hosts) != 1 || triggers[0].Hosts[0].HostID != hostID ||
+		len(triggers[0].Groups) != 1 || triggers[0].Groups[0].GroupID != groupID {
+		t.Errorf("triggers mismatch, got=%+v", triggers)
+	}
+}
+
+func TestServerPager(t *testing.T) {
+	s := zabbixtest.NewServer()
+	defer s.Close()
+	ctx := context.Background()
+	client := newLoggedInClient(t, s)
+
+	groupID := s.AddHostGroup("Linux servers")
+	for _, name := range []string{"server1", "server2", "server3"} {
+		s.AddHost(name, groupID)
+	}
+
+	p := zabbix.NewPager[host.Host](client, "host.get", map[string]any{
+		"output": host.OutputFields,
+	}, "hostid", 2)
+	var names []string
+	for p.Next(ctx) {
+		for _, h := range p.Page() {
+			names = append(names, h.Name)
+		}
+	}
+	if err := p.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := len(names), 3; got != want {
+		t.Errorf("host count mismatch, got=%d, want=%d", got, want)
+	}
+}
@@ -0,0 +1,226 @@
+package zabbixtest
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+)
+
+type trigger struct {
+	props   object
+	hostIDs []string
+}
+
+// AddTrigger adds a trigger and returns its ID. Hosts of the trigger are
+// taken from expression like "last(/host1/system.cpu.load)>5".
+// It panics if the trigger cannot be created.
+func (s *Server) AddTrigger(description, expression string) (triggerID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	ids, err := s.createTriggers([]triggerParams{{
+		Description: ptr(flexString(description)),
+		Expression:  ptr(flexString(expression)),
+	}})
+	if err != nil {
+		panic(fmt.Sprintf("zabbixtest: add trigger: %v", err))
+	}
+	return ids[0]
+}
+
+type triggerParams struct {
+	TriggerID   *flexString `json:"triggerid"`
+	Description *flexString `json:"description"`
+	Expression  *flexString `json:"expression"`
+	EventName   *flexString `json:"event_name"`
+	Comments    *flexString `json:"comments"`
+	Priority    *flexString `json:"priority"`
+	Status      *flexString `json:"status"`
+	URL         *flexString `json:"url"`
+}
+
+// expressionHostRegexp matches host names in item references in trigger
+// expressions like "/host1/system.cpu.load".
+var expressionHostRegexp = regexp.MustCompile(`\(/([^/]+)/`)
+
+// expressionHostIDs returns IDs of hosts referred in expression.
+func (s *Server) expressionHostIDs(expression string) ([]string, error) {
+	var hostIDs []string
+	for _, m := range expressionHostRegexp.FindAllStringSubmatch(expression, -1) {
+		h := s.hostByName(m[1])
+		if h == nil {
+			return nil, errInvalidParams("Incorrect trigger expression. Host %q does not exist or you have no access to this host.", m[1])
+		}
+		if !stringList(hostIDs).contains(h.props["hostid"]) {
+			hostIDs = append(hostIDs, h.props["hostid"])
+		}
+	}
+	if len(hostIDs) == 0 {
+		return nil, errInvalidParams("Trigger expression must contain at least one /host/key reference.")
+	}
+	return hostIDs, nil
+}
+
+func (s *Server) triggerGroupIDs(t *trigger) []string {
+	var groupIDs []string
+	for _, hostID := range t.hostIDs {
+		for _, groupID := range s.hosts[hostID].groupIDs {
+			if !stringList(groupIDs).contains(groupID) {
+				groupIDs = append(groupIDs, groupID)
+			}
+		}
+	}
+	return groupIDs
+}
+
+func (s *Server) triggerGet(params json.RawMessage) (any, error) {
+	p, err := decodeGetParams(params)
+	if err != nil {
+		return nil, err
+	}
+	objs := []object{}
+	for _, t := range s.triggers {
+		// Items are not supported, so no triggers match if itemids is
+		// specified.
+		if !idsMatch(p.TriggerIDs, t.props["triggerid"]) || !idsMatchAny(p.HostIDs, t.hostIDs) ||
+			!idsMatchAny(p.GroupIDs, s.triggerGroupIDs(t)) || !idsMatchAny(p.ItemIDs, nil) ||
+			!p.match(t.props) {
+			continue
+		}
+		objs = append(objs, t.props)
+	}
+	return p.result(objs, "triggerid", func(obj object, res map[string]any) error {
+		t := s.triggers[obj["triggerid"]]
+		if err := selectRelated(res, "groups", p.SelectGroups, s.hostGroupObjects(s.triggerGroupIDs(t))); err != nil {
+			return err
+		}
+		if err := selectRelated(res, "hosts", p.SelectHosts, s.hostObjects(t.hostIDs)); err != nil {
+			return err
+		}
+		return selectRelated(res, "items", p.SelectItems, nil)
+	})
+}
+
+func (s *Server) triggerCreate(params json.RawMessage) (any, error) {
+	triggers, err := decodeOneOrMany[triggerParams](params)
+	if err != nil {
+		return nil, err
+	}
+	ids, err := s.createTriggers(triggers)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]any{"triggerids": ids}, nil
+}
+
+func (s *Server) createTriggers(triggers []triggerParams) ([]string, error) {
+	created := make([]*trigger, len(triggers))
+	for i, params := range triggers {
+		if params.Description == nil || *params.Description == "" {
+			return nil, errMissingParam(i, "description")
+		}
+		if params.Expression == nil || *params.Expression == "" {
+			return nil, errMissingParam(i, "expression")
+		}
+		t := &trigger{props: object{
+			"triggerid":  s.newID("trigger"),
+			"event_name": "",
+			"comments":   "",
+			"error":      "",
+			"lastchange": "0",
+			"priority":   "0",
+			"state":      "0",
+			"status":     "0",
+			"url":        "",
+			"value":      "0",
+		}}
+		if err := s.applyTriggerParams(t, params); err != nil {
+			return nil, err
+		}
+		created[i] = t
+	}
+	ids := make([]string, len(created))
+	for i, t := range created {
+		ids[i] = t.props["triggerid"]
+		s.triggers[ids[i]] = t
+	}
+	return ids, nil
+}
+
+func (s *Server) triggerUpdate(params json.RawMessage) (any, error) {
+	triggers, err := decodeOneOrMany[triggerParams](params)
+	if err != nil {
+		return nil, err
+	}
+	updated := make([]*trigger, len(triggers))
+	for i, params := range triggers {
+		if params.TriggerID == nil {
+			return nil, errMissingParam(i, "triggerid")
+		}
+		orig, ok := s.triggers[string(*params.TriggerID)]
+		if !ok {
+			return nil, errNoPermissions()
+		}
+		t := &trigger{props: orig.props.clone(), hostIDs: orig.hostIDs}
+		if err := s.applyTriggerParams(t, params); err != nil {
+			return nil, err
+		}
+		if t.props["status"] != orig.props["status"] {
+			t.props["lastchange"] = strconv.FormatInt(s.now().Unix(), 10)
+		}
+		updated[i] = t
+	}
+	ids := make([]string, len(updated))
+	for i, t := range updated {
+		ids[i] = t.props["triggerid"]
+		s.triggers[ids[i]] = t
+	}
+	return map[string]any{"triggerids": ids}, nil
+}
+
+// applyTriggerParams validates params and sets them to t.
+func (s *Server) applyTriggerParams(t *trigger, params triggerParams) error {
+	if params.Expression != nil {
+		hostIDs, err := s.expressionHostIDs(string(*params.Expression))
+		if err != nil {
+			return err
+		}
+		t.hostIDs = hostIDs
+	}
+	setProp(t.props, "description", params.Description)
+	setProp(t.props, "expression", params.Expression)
+	setProp(t.props, "event_name", params.EventName)
+	setProp(t.props, "comments", params.Comments)
+	setProp(t.props, "priority", params.Priority)
+	setProp(t.props, "status", params.Status)
+	setProp(t.props, "url", params.URL)
+
+	if status := t.props["status"]; status != "0" && status != "1" {
+		return errInvalidParams("Invalid parameter \"/1/status\": value must be one of 0, 1.")
+	}
+	for id, other := range s.triggers {
+		if id != t.props["triggerid"] &&
+			other.props["description"] == t.props["description"] &&
+			other.props["expression"] == t.props["expression"] {
+			return errInvalidParams("Trigger %q already exists on %q.",
+				t.props["description"], s.hosts[t.hostIDs[0]].props["host"])
+		}
+	}
+	return nil
+}
+
+func (s *Server) triggerDelete(params json.RawMessage) (any, error) {
+	ids, err := decodeIDs(params)
+	if err != nil {
+		return nil, err
+	}
+	for _, id := range ids {
+		if _, ok := s.triggers[id]; !ok {
+			return nil, errNoPermissions()
+		}
+	}
+	for _, id := range ids {
+		delete(s.triggers, id)
+	}
+	return map[string]any{"triggerids": ids}, nil
+}
@@ -0,0 +1,81 @@
+package zabbixtest
+
+import "encoding/json"
+
+func (s *Server) apiInfoVersion(params json.RawMessage) (any, error) {
+	return s.apiVersion, nil
+}
+
+func (s *Server) userLogin(params json.RawMessage) (any, error) {
+	var p struct {
+		// "user" was renamed to "username" in Zabbix 6.4.
+		Username *string `json:"username"`
+		User     *string `json:"user"`
+		Password string  `json:"password"`
+		UserData bool    `json:"userData"`
+	}
+	if err := json.Unmarshal(params, &p); err != nil {
+		return nil, errInvalidParams("Invalid parameter \"/\": an array or object is expected.")
+	}
+	var username string
+	switch {
+	case p.Username != nil:
+		username = *p.Username
+	case p.User != nil:
+		username = *p.User
+	default:
+		return nil, errInvalidParams("Invalid parameter \"/\": the parameter \"username\" is missing.")
+	}
+
+	password, ok := s.users[username]
+	if !ok || password != p.Password {
+		return nil, errApplication("Incorrect user name or password or account is temporarily blocked.")
+	}
+	sessionID := newSessionID()
+	s.sessions[sessionID] = username
+	if p.UserData {
+		return map[string]any{
+			"username":  username,
+			"sessionid": sessionID,
+		}, nil
+	}
+	return sessionID, nil
+}
+
+func (s *Server) userLogout(params json.RawMessage) (any, error) {
+	if _, ok := s.sessions[s.auth]; !ok {
+		// The request was authenticated with an API token.
+		return nil, errApplication("Cannot log out.")
+	}
+	delete(s.sessions, s.auth)
+	return true, nil
+}
+
+func (s *Server) userCheckAuthentication(params json.RawMessage) (any, error) {
+	var p struct {
+		SessionID string `json:"sessionid"`
+		Token     string `json:"token"`
+	}
+	if err := json.Unmarshal(params, &p); err != nil {
+		return nil, errInvalidParams("Invalid parameter \"/\": an array or object is expected.")
+	}
+	switch {
+	case p.SessionID != "":
+		username, ok := s.sessions[p.SessionID]
+		if !ok {
+			return nil, errSessionTerminated()
+		}
+		return map[string]any{
+			"username":  username,
+			"sessionid": p.SessionID,
+		}, nil
+	case p.Token != "":
+		if _, ok := s.apiTokens[p.Token]; !ok {
+			return nil, errNotAuthorised()
+		}
+		// API tokens are not associated with users in this server.
+		return map[string]any{"username": ""}, nil
+	default:
+		return nil, errInvalidParams("Session ID or token is expected.")
+	}
+}